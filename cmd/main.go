@@ -1,9 +1,15 @@
 package main
 
 import (
+	"os"
+
 	"github.com/yasinsaee/go-user-service/internal/app"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		app.RunCommand(os.Args[1], os.Args[2:])
+		return
+	}
 	app.StartApp()
 }
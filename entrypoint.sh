@@ -3,9 +3,25 @@
 mkdir -p $(dirname "$PRIVATE_KEY_PATH")
 
 if [ ! -f "$PRIVATE_KEY_PATH" ] || [ ! -f "$PUBLIC_KEY_PATH" ]; then
-    echo "Generating RSA keys..."
-    openssl genrsa -out "$PRIVATE_KEY_PATH" 2048
-    openssl rsa -in "$PRIVATE_KEY_PATH" -pubout -out "$PUBLIC_KEY_PATH"
+    case "${JWT_ALGORITHM:-RS256}" in
+        ES256)
+            echo "Generating ECDSA P-256 keys..."
+            openssl genpkey -algorithm EC -pkeyopt ec_paramgen_curve:P-256 -out "$PRIVATE_KEY_PATH"
+            ;;
+        ES384)
+            echo "Generating ECDSA P-384 keys..."
+            openssl genpkey -algorithm EC -pkeyopt ec_paramgen_curve:P-384 -out "$PRIVATE_KEY_PATH"
+            ;;
+        EdDSA)
+            echo "Generating Ed25519 keys..."
+            openssl genpkey -algorithm ed25519 -out "$PRIVATE_KEY_PATH"
+            ;;
+        *)
+            echo "Generating RSA keys..."
+            openssl genrsa -out "$PRIVATE_KEY_PATH" 2048
+            ;;
+    esac
+    openssl pkey -in "$PRIVATE_KEY_PATH" -pubout -out "$PUBLIC_KEY_PATH"
 fi
 
 exec "$@"
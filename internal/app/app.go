@@ -21,6 +21,8 @@ func StartApp() {
 	config.LoadEnv()
 	InitMongo()
 	InitJWT()
+	StartKeyMaintenance()
 	InitRedis()
+	go StartHTTPServer()
 	StartGRPCServer()
 }
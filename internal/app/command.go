@@ -0,0 +1,148 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/yasinsaee/go-user-service/internal/app/config"
+	domain_permission "github.com/yasinsaee/go-user-service/internal/domain/permission"
+	domain_role "github.com/yasinsaee/go-user-service/internal/domain/role"
+	"github.com/yasinsaee/go-user-service/internal/middleware"
+	repository_permission "github.com/yasinsaee/go-user-service/internal/repository/permission"
+	repository_role "github.com/yasinsaee/go-user-service/internal/repository/role"
+	repository_user "github.com/yasinsaee/go-user-service/internal/repository/user"
+	"github.com/yasinsaee/go-user-service/internal/service/user"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+	"github.com/yasinsaee/go-user-service/pkg/mongo"
+)
+
+// RunCommand executes an administrative command instead of starting the servers.
+func RunCommand(name string, args []string) {
+	config.LoadEnv()
+
+	switch name {
+	case "rotate-keys":
+		rotateKeys(args)
+	case "grant-admin":
+		grantAdmin(args)
+	case "import-users":
+		importUsers(args)
+	default:
+		log.Fatalf("unknown command %q", name)
+	}
+}
+
+// rotateKeys forces a signing key rotation, e.g. after a suspected key compromise.
+func rotateKeys(args []string) {
+	fs := flag.NewFlagSet("rotate-keys", flag.ExitOnError)
+	immediate := fs.Bool("immediate", false, "sign with the new key right away instead of after the grace period")
+	fs.Parse(args)
+
+	if config.GetEnv("JWT_KEY_STORE", "file") == "mongo" {
+		InitMongo()
+	}
+	InitJWT()
+
+	key, err := jwt.RotateKey(*immediate)
+	if err != nil {
+		log.Fatalf("failed to rotate signing key: %v", err)
+	}
+	fmt.Printf("new signing key %s activates at %s\n", key.KID, key.ActivatesAt.Format("2006-01-02 15:04:05 MST"))
+}
+
+// grantAdmin gives a user the admin role, whose "*" permission passes every
+// authorization policy. It bootstraps the first administrator, who can then
+// manage roles and permissions over gRPC.
+func grantAdmin(args []string) {
+	fs := flag.NewFlagSet("grant-admin", flag.ExitOnError)
+	username := fs.String("username", "", "user to make an administrator")
+	fs.Parse(args)
+
+	if *username == "" {
+		log.Fatal("grant-admin: -username is required")
+	}
+
+	InitMongo()
+	permissionRepo := repository_permission.NewMongoPermissionRepository(mongo.DB.Database, "permission")
+	roleRepo := repository_role.NewMongoRoleRepository(mongo.DB.Database, "role")
+	userRepo := repository_user.NewMongoUserRepository(mongo.DB.Database, "user")
+
+	u, err := userRepo.FindByUsername(*username)
+	if err != nil {
+		log.Fatalf("failed to find user %q: %v", *username, err)
+	}
+
+	p, err := permissionRepo.FindByName(middleware.AnyPermission)
+	if err != nil {
+		p = &domain_permission.Permission{Name: middleware.AnyPermission, Description: "every permission"}
+		if err := permissionRepo.Create(p); err != nil {
+			log.Fatalf("failed to create permission: %v", err)
+		}
+	}
+
+	r, err := roleRepo.FindByName("admin")
+	if err != nil {
+		r = &domain_role.Role{Name: "admin", Description: "administrator"}
+		if err := roleRepo.Create(r); err != nil {
+			log.Fatalf("failed to create role: %v", err)
+		}
+	}
+	if !slices.Contains(r.Permissions, p.ID) {
+		r.Permissions = append(r.Permissions, p.ID)
+		if err := roleRepo.Update(r); err != nil {
+			log.Fatalf("failed to update role: %v", err)
+		}
+	}
+
+	if !slices.Contains(u.Roles, r.ID) {
+		u.Roles = append(u.Roles, r.ID)
+		if err := userRepo.Update(u); err != nil {
+			log.Fatalf("failed to update user: %v", err)
+		}
+	}
+	fmt.Printf("%s is an administrator, tokens issued from the next login carry the %q permission\n", u.Username, middleware.AnyPermission)
+}
+
+// importUsers creates the users of a JSONL or CSV file exported from another application.
+// Their password hashes are kept and replaced by native ones at their first login.
+func importUsers(args []string) {
+	fs := flag.NewFlagSet("import-users", flag.ExitOnError)
+	file := fs.String("file", "", "JSONL or CSV file of the users")
+	format := fs.String("format", "", "jsonl or csv, taken from the file extension when empty")
+	dryRun := fs.Bool("dry-run", false, "check the users without creating them")
+	fs.Parse(args)
+
+	if *file == "" {
+		log.Fatal("import-users: -file is required")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("failed to open %s: %v", *file, err)
+	}
+	defer f.Close()
+
+	InitMongo()
+	userRepo := repository_user.NewMongoUserRepository(mongo.DB.Database, "user")
+
+	result, err := user.NewUserImporter(userRepo).Import(f, *format, *dryRun)
+	if err != nil {
+		log.Fatalf("failed to import users: %v", err)
+	}
+	for _, failure := range result.Failures {
+		fmt.Printf("line %d: %s: %s\n", failure.Line, failure.Username, failure.Reason)
+	}
+	if *dryRun {
+		fmt.Printf("%d users can be imported, %d failed\n", result.Imported, len(result.Failures))
+		return
+	}
+	fmt.Printf("%d users imported, %d failed\n", result.Imported, len(result.Failures))
+}
@@ -6,23 +6,45 @@ import (
 	"strconv"
 
 	"github.com/yasinsaee/go-user-service/internal/app/config"
+	mfa_config "github.com/yasinsaee/go-user-service/internal/domain/mfa/config"
 	otp_config "github.com/yasinsaee/go-user-service/internal/domain/otp/config"
 	"github.com/yasinsaee/go-user-service/internal/domain/otp/providers"
+	passkey_config "github.com/yasinsaee/go-user-service/internal/domain/passkey/config"
+	"github.com/yasinsaee/go-user-service/internal/domain/security/publishers"
+	apikeygrpc "github.com/yasinsaee/go-user-service/internal/handlers/grpc/apikey"
+	authgrpc "github.com/yasinsaee/go-user-service/internal/handlers/grpc/auth"
+	clientgrpc "github.com/yasinsaee/go-user-service/internal/handlers/grpc/client"
 	otpgrpc "github.com/yasinsaee/go-user-service/internal/handlers/grpc/otp"
 	permissiongrpc "github.com/yasinsaee/go-user-service/internal/handlers/grpc/permission"
 	rolegrpc "github.com/yasinsaee/go-user-service/internal/handlers/grpc/role"
 	usergrpc "github.com/yasinsaee/go-user-service/internal/handlers/grpc/user"
+	"github.com/yasinsaee/go-user-service/internal/middleware"
+	repository_apikey "github.com/yasinsaee/go-user-service/internal/repository/apikey"
+	repository_client "github.com/yasinsaee/go-user-service/internal/repository/client"
 	repository_otp "github.com/yasinsaee/go-user-service/internal/repository/otp"
 	repository_permission "github.com/yasinsaee/go-user-service/internal/repository/permission"
 	repository_role "github.com/yasinsaee/go-user-service/internal/repository/role"
 	repository_user "github.com/yasinsaee/go-user-service/internal/repository/user"
+	"github.com/yasinsaee/go-user-service/internal/service/apikey"
+	"github.com/yasinsaee/go-user-service/internal/service/client"
+	"github.com/yasinsaee/go-user-service/internal/service/mfa"
+	trusted_device_store "github.com/yasinsaee/go-user-service/internal/service/mfa/device/redis"
+	mfa_attempt_store "github.com/yasinsaee/go-user-service/internal/service/mfa/redis"
 	"github.com/yasinsaee/go-user-service/internal/service/otp"
 	ratelimiter "github.com/yasinsaee/go-user-service/internal/service/otp/redis"
+	"github.com/yasinsaee/go-user-service/internal/service/passkey"
+	passkey_ceremony_store "github.com/yasinsaee/go-user-service/internal/service/passkey/redis"
 	"github.com/yasinsaee/go-user-service/internal/service/permission"
 	"github.com/yasinsaee/go-user-service/internal/service/role"
+	"github.com/yasinsaee/go-user-service/internal/service/token"
+	token_revocation_store "github.com/yasinsaee/go-user-service/internal/service/token/redis"
 	"github.com/yasinsaee/go-user-service/internal/service/user"
 	user_token_store "github.com/yasinsaee/go-user-service/internal/service/user/redis"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
 	"github.com/yasinsaee/go-user-service/pkg/mongo"
+	apikeypb "github.com/yasinsaee/go-user-service/user-service/apikey"
+	authpb "github.com/yasinsaee/go-user-service/user-service/auth"
+	clientpb "github.com/yasinsaee/go-user-service/user-service/client"
 	otppb "github.com/yasinsaee/go-user-service/user-service/otp"
 	permissionpb "github.com/yasinsaee/go-user-service/user-service/permission"
 	rolepb "github.com/yasinsaee/go-user-service/user-service/role"
@@ -37,45 +59,84 @@ func StartGRPCServer() {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	// s := grpc.NewServer(grpc.UnaryInterceptor(middleware.AuthInterceptor()))
-	s := grpc.NewServer()
-
 	//repos
 	permissionRepo := repository_permission.NewMongoPermissionRepository(mongo.DB.Database, "permission")
 	roleRepo := repository_role.NewMongoRoleRepository(mongo.DB.Database, "role")
 	userRepo := repository_user.NewMongoUserRepository(mongo.DB.Database, "user")
 	otpRepo := repository_otp.NewMongoOTPRepository(mongo.DB.Database, "otp")
+	clientRepo := repository_client.NewMongoClientRepository(mongo.DB.Database, "client")
+	apiKeyRepo := repository_apikey.NewMongoApiKeyRepository(mongo.DB.Database, "api_key")
 
 	//providers
 	provider := providers.NewOTPProvider()
 
+	//security events
+	eventPublisher := publishers.NewEventPublisher()
+
 	//otp config
 	otpConfig := otp_config.LoadOTPConfig()
 
+	//mfa config
+	mfaConfig := mfa_config.LoadMFAConfig()
+
 	//redis-based
 	////rate limiter
-	rateLimiter := ratelimiter.NewRedisOTPRateLimiter(int(otpConfig.RateLimit))
+	rateLimiter := ratelimiter.NewRedisOTPRateLimiter(int(otpConfig.RateLimit), otpConfig.MaxAttempts)
 	////token store
 	refreshExp, _ := strconv.Atoi(config.GetEnv("JWT_REFRESH_TOKEN_EXP", ""))
 	tokenStore := user_token_store.NewRefreshTokenStore(int64(refreshExp))
+	////revocation list
+	revocationStore := token_revocation_store.NewRevocationStore(jwt.MaxTokenLifetime())
+	////failed mfa codes
+	mfaAttemptStore := mfa_attempt_store.NewAttemptStore()
+	////devices that skip the second factor
+	trustedDeviceStore := trusted_device_store.NewTrustedDeviceStore()
+	////webauthn ceremonies
+	passkeyCeremonyStore := passkey_ceremony_store.NewCeremonyStore()
 
 	//services
+	passwordHasher := newPasswordHasher()
 	permissionService := permission.NewPermissionService(permissionRepo)
 	roleService := role.NewRoleService(roleRepo)
-	userService := user.NewUserService(userRepo, tokenStore)
 	otpService := otp.NewOTPService(otpRepo, provider, rateLimiter, otpConfig.TTL, otpConfig.RateLimit, otpConfig, otpConfig.MaxOTPPerReceiver)
+	tokenService := token.NewTokenService(revocationStore, tokenStore)
+	userService := user.NewUserService(userRepo, tokenStore, trustedDeviceStore, eventPublisher, tokenService, newPasswordPolicy(passwordHasher, roleRepo), passwordHasher, apiKeyRepo)
+	userImporter := user.NewUserImporter(userRepo)
+	clientService := client.NewClientService(clientRepo, passwordHasher)
+	apiKeyService := apikey.NewApiKeyService(apiKeyRepo, userRepo, roleService, permissionService)
+	mfaService := mfa.NewMFAService(userRepo, mfaAttemptStore, trustedDeviceStore, mfaConfig)
+	passkeyService, err := passkey.NewPasskeyService(passkey_config.LoadPasskeyConfig(), userRepo, passkeyCeremonyStore, eventPublisher)
+	if err != nil {
+		log.Fatalf("failed to configure passkeys: %v", err)
+	}
+
+	//every validation in pkg/jwt consults the revocation list
+	jwt.SetRevocationList(tokenService)
+
+	//server, every call is authorized against its policy
+	policies := grpcPolicies()
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(middleware.AuthInterceptor(policies, apiKeyService)),
+		grpc.StreamInterceptor(middleware.StreamAuthInterceptor(policies, apiKeyService)),
+	)
 
 	//handlers
 	permissionHandler := permissiongrpc.New(permissionService)
 	roleHandler := rolegrpc.New(roleService, permissionService)
-	userHandler := usergrpc.New(userService, roleService, permissionService)
+	userHandler := usergrpc.New(userService, roleService, permissionService, tokenService, otpService, mfaService, passkeyService, userImporter, otpConfig.LoginAutoRegister, trustedProxies())
 	otpHandler := otpgrpc.New(otpService)
+	authHandler := authgrpc.New(tokenService, clientService)
+	clientHandler := clientgrpc.New(clientService, tokenService)
+	apiKeyHandler := apikeygrpc.New(apiKeyService)
 
 	//register grpc services
 	permissionpb.RegisterPermissionServiceServer(s, permissionHandler)
 	rolepb.RegisterRoleServiceServer(s, roleHandler)
 	userpb.RegisterUserServiceServer(s, userHandler)
 	otppb.RegisterOTPServiceServer(s, otpHandler)
+	authpb.RegisterAuthServiceServer(s, authHandler)
+	clientpb.RegisterClientServiceServer(s, clientHandler)
+	apikeypb.RegisterApiKeyServiceServer(s, apiKeyHandler)
 
 	log.Println("gRPC server is running on port 50051")
 	if err := s.Serve(lis); err != nil {
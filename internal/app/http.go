@@ -0,0 +1,25 @@
+package app
+
+import (
+	"log"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yasinsaee/go-user-service/internal/app/config"
+	"github.com/yasinsaee/go-user-service/internal/context"
+)
+
+func StartHTTPServer() {
+	port := config.GetEnv("HTTP_PORT", "8080")
+
+	e := echo.New()
+	e.HideBanner = true
+	e.IPExtractor = ipExtractor(trustedProxies())
+	e.Use(context.InitContext)
+
+	Register(e)
+
+	log.Println("HTTP server is running on port " + port)
+	if err := e.Start(":" + port); err != nil {
+		log.Fatalf("failed to serve http: %v", err)
+	}
+}
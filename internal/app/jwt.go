@@ -2,11 +2,17 @@ package app
 
 import (
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/yasinsaee/go-user-service/internal/app/config"
+	mfa_config "github.com/yasinsaee/go-user-service/internal/domain/mfa/config"
+	repository_signingkey "github.com/yasinsaee/go-user-service/internal/repository/signingkey"
 	"github.com/yasinsaee/go-user-service/pkg/jwt"
 	"github.com/yasinsaee/go-user-service/pkg/logger"
+	"github.com/yasinsaee/go-user-service/pkg/mongo"
 )
 
 func loadKey(path string) []byte {
@@ -18,6 +24,28 @@ func loadKey(path string) []byte {
 	return data
 }
 
+// loadOptionalKey reads a key file that only seeds the key ring on first start.
+func loadOptionalKey(path string) []byte {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logger.Warn("key file not loaded: ", err.Error())
+		return nil
+	}
+	return data
+}
+
+func newKeyStore() jwt.KeyStore {
+	switch config.GetEnv("JWT_KEY_STORE", "file") {
+	case "mongo":
+		return repository_signingkey.NewMongoSigningKeyRepository(mongo.DB.Database, "signing_key")
+	case "file":
+		privateKeyPath := config.GetEnv("PRIVATE_KEY_PATH", "../keys/private.key")
+		return jwt.NewFileKeyStore(config.GetEnv("JWT_KEY_DIR", filepath.Join(filepath.Dir(privateKeyPath), "ring")))
+	default:
+		return nil
+	}
+}
+
 func InitJWT() {
 	accessExp, err := strconv.Atoi(config.GetEnv("JWT_ACCESS_TOKEN_EXP", "1"))
 	if err != nil {
@@ -28,10 +56,54 @@ func InitJWT() {
 	if err != nil {
 		logger.Error("youre expire date jwt is not ok")
 	}
-	jwt.Init(jwt.JWTConfig{
-		PrivateKey:      loadKey(config.GetEnv("PRIVATE_KEY_PATH", "../keys/private.key")),
-		PublicKey:       loadKey(config.GetEnv("PUBLIC_KEY_PATH", "../keys/public.key")),
-		AccessTokenExp:  accessExp,
-		RefreshTokenExp: refreshExp,
-	})
+
+	clientExp, _ := strconv.Atoi(config.GetEnv("JWT_CLIENT_TOKEN_EXP_MINUTES", "15"))
+
+	leeway, _ := strconv.Atoi(config.GetEnv("JWT_LEEWAY_SECONDS", "30"))
+	var audience []string
+	for _, aud := range strings.Split(config.GetEnv("JWT_AUDIENCE", ""), ",") {
+		if aud = strings.TrimSpace(aud); aud != "" {
+			audience = append(audience, aud)
+		}
+	}
+
+	rotationDays, _ := strconv.Atoi(config.GetEnv("JWT_KEY_ROTATION_DAYS", "0"))
+	graceHours, _ := strconv.Atoi(config.GetEnv("JWT_KEY_GRACE_HOURS", "1"))
+	acceptLegacy, _ := strconv.ParseBool(config.GetEnv("JWT_ACCEPT_LEGACY_TOKENS", "true"))
+
+	cfg := jwt.JWTConfig{
+		AccessTokenExp:      accessExp,
+		RefreshTokenExp:     refreshExp,
+		ClientTokenExp:      time.Duration(clientExp) * time.Minute,
+		DeviceTokenExp:      mfa_config.LoadMFAConfig().TrustedDeviceTTL,
+		KeyStore:            newKeyStore(),
+		RotationInterval:    time.Duration(rotationDays) * 24 * time.Hour,
+		RotationGracePeriod: time.Duration(graceHours) * time.Hour,
+		Algorithm:           config.GetEnv("JWT_ALGORITHM", jwt.AlgorithmRS256),
+		Issuer:              config.GetEnv("JWT_ISSUER", "go-user-service"),
+		Audience:            audience,
+		Leeway:              time.Duration(leeway) * time.Second,
+		AcceptLegacyTokens:  acceptLegacy,
+	}
+	if cfg.KeyStore == nil {
+		cfg.PrivateKey = loadKey(config.GetEnv("PRIVATE_KEY_PATH", "../keys/private.key"))
+		cfg.PublicKey = loadKey(config.GetEnv("PUBLIC_KEY_PATH", "../keys/public.key"))
+	} else {
+		cfg.PrivateKey = loadOptionalKey(config.GetEnv("PRIVATE_KEY_PATH", "../keys/private.key"))
+		cfg.PublicKey = loadOptionalKey(config.GetEnv("PUBLIC_KEY_PATH", "../keys/public.key"))
+	}
+
+	if err := jwt.Init(cfg); err != nil {
+		panic("cannot init jwt keys: " + err.Error())
+	}
+}
+
+// StartKeyMaintenance keeps the key ring in sync with the store so keys rotated
+// by another replica or by the rotate-keys command are picked up.
+func StartKeyMaintenance() {
+	reload, _ := strconv.Atoi(config.GetEnv("JWT_KEY_RELOAD_SECONDS", "60"))
+	if reload <= 0 {
+		return
+	}
+	go jwt.MaintainKeys(time.Duration(reload) * time.Second)
 }
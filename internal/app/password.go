@@ -0,0 +1,74 @@
+package app
+
+import (
+	"bytes"
+	"log"
+	"os"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/role"
+	domain_user "github.com/yasinsaee/go-user-service/internal/domain/user"
+	user_config "github.com/yasinsaee/go-user-service/internal/domain/user/config"
+	"github.com/yasinsaee/go-user-service/internal/service/user"
+	"github.com/yasinsaee/go-user-service/pkg/password"
+)
+
+// newPasswordPolicy builds the policy new passwords are checked against, with the
+// breached password list when PASSWORD_BREACHED_DIR points to one.
+func newPasswordPolicy(hasher domain_user.PasswordHasher, roles role.RoleRepository) domain_user.PasswordPolicy {
+	policyConfig := user_config.LoadPasswordPolicyConfig()
+
+	var breached domain_user.BreachedPasswords
+	if policyConfig.BreachedDir != "" {
+		b, err := user.NewBreachedPasswords(policyConfig.BreachedDir)
+		if err != nil {
+			log.Fatalf("failed to load breached passwords: %v", err)
+		}
+		breached = b
+	}
+	return user.NewPasswordPolicy(policyConfig, breached, hasher, roles)
+}
+
+// newPasswordHasher builds the hasher of user passwords. Both Argon2id and bcrypt hashes
+// verify, those of the other algorithm are replaced at the next login.
+func newPasswordHasher() domain_user.PasswordHasher {
+	hashConfig := user_config.LoadPasswordHashConfig()
+
+	var pepper []byte
+	if hashConfig.PepperFile != "" {
+		pepper = readPepper(hashConfig.PepperFile)
+	}
+	var previous [][]byte
+	for _, file := range hashConfig.PreviousPepperFiles {
+		previous = append(previous, readPepper(file))
+	}
+
+	params := password.DefaultArgon2idParams
+	params.Memory = uint32(hashConfig.Argon2Memory)
+	params.Iterations = uint32(hashConfig.Argon2Iterations)
+	params.Parallelism = uint8(hashConfig.Argon2Parallelism)
+	argon2id := password.NewArgon2id(params, pepper, previous...)
+	bcrypt := password.NewBcrypt(hashConfig.BcryptCost)
+
+	switch hashConfig.Algorithm {
+	case user_config.HashArgon2id:
+		return password.NewHasher(argon2id, bcrypt)
+	case user_config.HashBcrypt:
+		return password.NewHasher(bcrypt, argon2id)
+	default:
+		log.Fatalf("unknown password hash algorithm %q", hashConfig.Algorithm)
+		return nil
+	}
+}
+
+// readPepper reads a pepper from file, without the surrounding white space.
+func readPepper(file string) []byte {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		log.Fatalf("failed to read password pepper: %v", err)
+	}
+	pepper := bytes.TrimSpace(data)
+	if len(pepper) == 0 {
+		log.Fatalf("password pepper file %s is empty", file)
+	}
+	return pepper
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/permission"
+	"github.com/yasinsaee/go-user-service/internal/middleware"
+	apikeypb "github.com/yasinsaee/go-user-service/user-service/apikey"
+	authpb "github.com/yasinsaee/go-user-service/user-service/auth"
+	clientpb "github.com/yasinsaee/go-user-service/user-service/client"
+	otppb "github.com/yasinsaee/go-user-service/user-service/otp"
+	permissionpb "github.com/yasinsaee/go-user-service/user-service/permission"
+	rolepb "github.com/yasinsaee/go-user-service/user-service/role"
+	userpb "github.com/yasinsaee/go-user-service/user-service/user"
+)
+
+// recentAuth is how long after signing in, or stepping up, a user may change how they sign in.
+const recentAuth = 15 * time.Minute
+
+// grpcPolicies lists who may call every gRPC method, a method missing here cannot be called at all.
+func grpcPolicies() middleware.Policies {
+	return middleware.Policies{
+		//user
+		userpb.UserService_Login_FullMethodName:                     middleware.PublicMethod(),
+		userpb.UserService_LoginWithOTP_FullMethodName:              middleware.PublicMethod(),
+		userpb.UserService_VerifyMFA_FullMethodName:                 middleware.PublicMethod(), // proves the mfa token
+		userpb.UserService_Register_FullMethodName:                  middleware.PublicMethod(),
+		userpb.UserService_ResetPassword_FullMethodName:             middleware.PublicMethod(), // proves the current password
+		userpb.UserService_RefreshToken_FullMethodName:              middleware.PublicMethod(), // proves the refresh token
+		userpb.UserService_StepUp_FullMethodName:                    middleware.Authenticated(),
+		userpb.UserService_Logout_FullMethodName:                    middleware.PublicMethod(), // proves the refresh token
+		userpb.UserService_Update_FullMethodName:                    middleware.RequirePermissions(permission.UserUpdate),
+		userpb.UserService_UpdatePassword_FullMethodName:            middleware.PublicMethod(), // proves the password change token or checks user.update_password
+		userpb.UserService_RequirePasswordChange_FullMethodName:     middleware.RequirePermissions(permission.UserUpdatePassword),
+		userpb.UserService_SetUserActive_FullMethodName:             middleware.RequirePermissions(permission.UserSetActive),
+		userpb.UserService_LogoutAll_FullMethodName:                 middleware.Authenticated().WithSessionOnly(),
+		userpb.UserService_ListSessions_FullMethodName:              middleware.Authenticated().WithSessionOnly(),
+		userpb.UserService_RevokeSession_FullMethodName:             middleware.Authenticated().WithSessionOnly(),
+		userpb.UserService_RevokeAllOtherSessions_FullMethodName:    middleware.Authenticated().WithSessionOnly(),
+		userpb.UserService_EnrollTOTP_FullMethodName:                middleware.Authenticated().WithMaxAuthAge(recentAuth),
+		userpb.UserService_ConfirmTOTP_FullMethodName:               middleware.Authenticated(),
+		userpb.UserService_DisableMFA_FullMethodName:                middleware.Authenticated(),
+		userpb.UserService_RegenerateRecoveryCodes_FullMethodName:   middleware.Authenticated(),
+		userpb.UserService_ListTrustedDevices_FullMethodName:        middleware.Authenticated(),
+		userpb.UserService_RevokeTrustedDevice_FullMethodName:       middleware.Authenticated(),
+		userpb.UserService_BeginPasskeyRegistration_FullMethodName:  middleware.Authenticated().WithMaxAuthAge(recentAuth),
+		userpb.UserService_FinishPasskeyRegistration_FullMethodName: middleware.Authenticated().WithMaxAuthAge(recentAuth),
+		userpb.UserService_ListPasskeys_FullMethodName:              middleware.Authenticated(),
+		userpb.UserService_DeletePasskey_FullMethodName:             middleware.Authenticated().WithMaxAuthAge(recentAuth),
+		userpb.UserService_BeginPasskeyLogin_FullMethodName:         middleware.PublicMethod(),
+		userpb.UserService_FinishPasskeyLogin_FullMethodName:        middleware.PublicMethod(), // proves the passkey
+		userpb.UserService_ImportUsers_FullMethodName:               middleware.RequirePermissions(permission.UserImport),
+
+		//role
+		rolepb.RoleService_GetRole_FullMethodName:    middleware.RequirePermissions(permission.RoleRead),
+		rolepb.RoleService_ListRoles_FullMethodName:  middleware.RequirePermissions(permission.RoleRead),
+		rolepb.RoleService_CreateRole_FullMethodName: middleware.RequirePermissions(permission.RoleCreate),
+		rolepb.RoleService_UpdateRole_FullMethodName: middleware.RequirePermissions(permission.RoleUpdate),
+		rolepb.RoleService_DeleteRole_FullMethodName: middleware.RequirePermissions(permission.RoleDelete),
+
+		//permission
+		permissionpb.PermissionService_GetPermission_FullMethodName:    middleware.RequirePermissions(permission.PermissionRead),
+		permissionpb.PermissionService_ListPermissions_FullMethodName:  middleware.RequirePermissions(permission.PermissionRead),
+		permissionpb.PermissionService_CreatePermission_FullMethodName: middleware.RequirePermissions(permission.PermissionCreate),
+		permissionpb.PermissionService_UpdatePermission_FullMethodName: middleware.RequirePermissions(permission.PermissionUpdate),
+		permissionpb.PermissionService_DeletePermission_FullMethodName: middleware.RequirePermissions(permission.PermissionDelete),
+
+		//otp
+		otppb.OTPService_RequestOTP_FullMethodName:  middleware.PublicMethod(),
+		otppb.OTPService_ValidateOTP_FullMethodName: middleware.PublicMethod(),
+		otppb.OTPService_GetOTP_FullMethodName:      middleware.RequirePermissions(permission.OTPRead),
+		otppb.OTPService_ListOTPs_FullMethodName:    middleware.RequirePermissions(permission.OTPRead),
+		otppb.OTPService_CreateOTP_FullMethodName:   middleware.RequirePermissions(permission.OTPCreate),
+		otppb.OTPService_UpdateOTP_FullMethodName:   middleware.RequirePermissions(permission.OTPUpdate),
+		otppb.OTPService_DeleteOTP_FullMethodName:   middleware.RequirePermissions(permission.OTPDelete),
+
+		//auth
+		authpb.AuthService_GetJWKS_FullMethodName:          middleware.PublicMethod(),
+		authpb.AuthService_IntrospectToken_FullMethodName:  middleware.PublicMethod(), // authenticates the client itself
+		authpb.AuthService_RevokeToken_FullMethodName:      middleware.RequirePermissions(permission.TokenRevoke),
+		authpb.AuthService_RevokeUserTokens_FullMethodName: middleware.RequirePermissions(permission.TokenRevoke),
+
+		//client
+		clientpb.ClientService_CreateClient_FullMethodName: middleware.RequirePermissions(permission.ClientCreate),
+		clientpb.ClientService_GetClient_FullMethodName:    middleware.RequirePermissions(permission.ClientRead),
+		clientpb.ClientService_ListClients_FullMethodName:  middleware.RequirePermissions(permission.ClientRead),
+		clientpb.ClientService_UpdateClient_FullMethodName: middleware.RequirePermissions(permission.ClientUpdate),
+		clientpb.ClientService_DeleteClient_FullMethodName: middleware.RequirePermissions(permission.ClientDelete),
+
+		//api key, acting on other users' keys is checked by the handler
+		apikeypb.ApiKeyService_CreateApiKey_FullMethodName: middleware.Authenticated().WithMaxAuthAge(recentAuth),
+		apikeypb.ApiKeyService_ListApiKeys_FullMethodName:  middleware.Authenticated(),
+		apikeypb.ApiKeyService_DeleteApiKey_FullMethodName: middleware.Authenticated(),
+	}
+}
+
+// restPolicies lists who may call every HTTP route, keyed by method and path as registered in echo.
+func restPolicies() middleware.Policies {
+	return middleware.Policies{
+		//jwks
+		"GET /.well-known/jwks.json": middleware.PublicMethod(),
+
+		//oidc
+		"GET /.well-known/openid-configuration": middleware.PublicMethod(),
+		"GET /userinfo":                         middleware.Authenticated(),
+		"POST /userinfo":                        middleware.Authenticated(),
+
+		//oauth, the user or the client authenticates itself
+		"GET /oauth/authorize":                    middleware.PublicMethod(),
+		"POST /oauth/authorize":                   middleware.PublicMethod(),
+		"GET /oauth/federated/:provider":          middleware.PublicMethod(),
+		"GET /oauth/federated/:provider/callback": middleware.PublicMethod(),
+		"POST /oauth/token":                       middleware.PublicMethod(),
+		"POST /oauth/introspect":                  middleware.PublicMethod(),
+
+		//role
+		"POST /roles":       middleware.RequirePermissions(permission.RoleCreate),
+		"GET /roles/:id":    middleware.RequirePermissions(permission.RoleRead),
+		"GET /roles":        middleware.RequirePermissions(permission.RoleRead),
+		"PUT /roles/:id":    middleware.RequirePermissions(permission.RoleUpdate),
+		"DELETE /roles/:id": middleware.RequirePermissions(permission.RoleDelete),
+
+		//permission
+		"POST /permissions":       middleware.RequirePermissions(permission.PermissionCreate),
+		"GET /permissions/:id":    middleware.RequirePermissions(permission.PermissionRead),
+		"GET /permissions":        middleware.RequirePermissions(permission.PermissionRead),
+		"PUT /permissions/:id":    middleware.RequirePermissions(permission.PermissionUpdate),
+		"DELETE /permissions/:id": middleware.RequirePermissions(permission.PermissionDelete),
+	}
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"log"
+	"net"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yasinsaee/go-user-service/internal/app/config"
+)
+
+// trustedProxies parses TRUSTED_PROXIES, the comma separated addresses or CIDR ranges of
+// the proxies in front of the service. Only they may tell the client address.
+func trustedProxies() []*net.IPNet {
+	var proxies []*net.IPNet
+	for _, entry := range strings.Split(config.GetEnv("TRUSTED_PROXIES", ""), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, n, err := net.ParseCIDR(entry)
+		if err != nil {
+			log.Fatalf("invalid trusted proxy %q: %v", entry, err)
+		}
+		proxies = append(proxies, n)
+	}
+	return proxies
+}
+
+// ipExtractor reads X-Forwarded-For only when the request comes through one of the proxies,
+// otherwise the address of the connection is the client.
+func ipExtractor(proxies []*net.IPNet) echo.IPExtractor {
+	if len(proxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, n := range proxies {
+		options = append(options, echo.TrustIPRange(n))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
@@ -1,30 +1,83 @@
 package app
 
 import (
+	"strconv"
+
 	"github.com/labstack/echo/v4"
+	"github.com/yasinsaee/go-user-service/internal/app/config"
+	federation_config "github.com/yasinsaee/go-user-service/internal/domain/federation/config"
+	mfa_config "github.com/yasinsaee/go-user-service/internal/domain/mfa/config"
+	"github.com/yasinsaee/go-user-service/internal/domain/security/publishers"
+	handler_jwks "github.com/yasinsaee/go-user-service/internal/handlers/rest/jwks"
+	handler_oauth "github.com/yasinsaee/go-user-service/internal/handlers/rest/oauth"
 	handler_permission "github.com/yasinsaee/go-user-service/internal/handlers/rest/permission"
 	role_permission "github.com/yasinsaee/go-user-service/internal/handlers/rest/role"
+	"github.com/yasinsaee/go-user-service/internal/middleware"
+	repository_apikey "github.com/yasinsaee/go-user-service/internal/repository/apikey"
+	repository_client "github.com/yasinsaee/go-user-service/internal/repository/client"
 	repository_permission "github.com/yasinsaee/go-user-service/internal/repository/permission"
 	repository_role "github.com/yasinsaee/go-user-service/internal/repository/role"
+	repository_user "github.com/yasinsaee/go-user-service/internal/repository/user"
+	"github.com/yasinsaee/go-user-service/internal/service/apikey"
+	"github.com/yasinsaee/go-user-service/internal/service/client"
+	"github.com/yasinsaee/go-user-service/internal/service/federation"
+	login_state_store "github.com/yasinsaee/go-user-service/internal/service/federation/redis"
+	"github.com/yasinsaee/go-user-service/internal/service/mfa"
+	trusted_device_store "github.com/yasinsaee/go-user-service/internal/service/mfa/device/redis"
+	mfa_attempt_store "github.com/yasinsaee/go-user-service/internal/service/mfa/redis"
+	"github.com/yasinsaee/go-user-service/internal/service/oauth"
+	authorization_code_store "github.com/yasinsaee/go-user-service/internal/service/oauth/redis"
 	"github.com/yasinsaee/go-user-service/internal/service/permission"
 	"github.com/yasinsaee/go-user-service/internal/service/role"
+	"github.com/yasinsaee/go-user-service/internal/service/token"
+	token_revocation_store "github.com/yasinsaee/go-user-service/internal/service/token/redis"
+	"github.com/yasinsaee/go-user-service/internal/service/user"
+	user_token_store "github.com/yasinsaee/go-user-service/internal/service/user/redis"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
 	"github.com/yasinsaee/go-user-service/pkg/mongo"
 )
 
 func Register(e *echo.Echo) {
 	permissionRepo := repository_permission.NewMongoPermissionRepository(mongo.DB.Database, "permission")
 	roleRepo := repository_role.NewMongoRoleRepository(mongo.DB.Database, "role")
-	// userRepo := repository_user.NewMongoUserRepository(mongo.DB.Database, "user")
+	clientRepo := repository_client.NewMongoClientRepository(mongo.DB.Database, "client")
+	userRepo := repository_user.NewMongoUserRepository(mongo.DB.Database, "user")
+	apiKeyRepo := repository_apikey.NewMongoApiKeyRepository(mongo.DB.Database, "api_key")
+
+	refreshExp, _ := strconv.Atoi(config.GetEnv("JWT_REFRESH_TOKEN_EXP", ""))
+	tokenStore := user_token_store.NewRefreshTokenStore(int64(refreshExp))
+	revocationStore := token_revocation_store.NewRevocationStore(jwt.MaxTokenLifetime())
+	authorizationCodeStore := authorization_code_store.NewAuthorizationCodeStore()
+	loginStateStore := login_state_store.NewLoginStateStore()
+	mfaAttemptStore := mfa_attempt_store.NewAttemptStore()
+	trustedDeviceStore := trusted_device_store.NewTrustedDeviceStore()
+	eventPublisher := publishers.NewEventPublisher()
 
+	mfaConfig := mfa_config.LoadMFAConfig()
+
+	passwordHasher := newPasswordHasher()
 	permissionService := permission.NewPermissionService(permissionRepo)
 	roleService := role.NewRoleService(roleRepo)
-	// userService := user.NewUserService(userRepo)
+	clientService := client.NewClientService(clientRepo, passwordHasher)
+	tokenService := token.NewTokenService(revocationStore, tokenStore)
+	apiKeyService := apikey.NewApiKeyService(apiKeyRepo, userRepo, roleService, permissionService)
+	userService := user.NewUserService(userRepo, tokenStore, trustedDeviceStore, eventPublisher, tokenService, newPasswordPolicy(passwordHasher, roleRepo), passwordHasher, apiKeyRepo)
+	authorizationService := oauth.NewAuthorizationService(authorizationCodeStore)
+	federationService := federation.NewFederationService(federation_config.LoadProviders(), loginStateStore, userRepo, passwordHasher)
+	mfaService := mfa.NewMFAService(userRepo, mfaAttemptStore, trustedDeviceStore, mfaConfig)
 
 	permissionHandler := handler_permission.NewPermissionHandler(permissionService)
 	roleHandler := role_permission.NewRoleHandler(roleService)
+	jwksHandler := handler_jwks.NewJWKSHandler()
+	oauthHandler := handler_oauth.NewOAuthHandler(tokenService, clientService, authorizationService, userService, roleService, permissionService, federationService, mfaService)
 	// userHandler := user_permission.NewUserHandler(userService)
 
+	// every route is authorized against its policy
+	e.Use(middleware.EchoAuth(restPolicies(), apiKeyService))
+
 	permissionHandler.RegisterRoutes(e)
 	roleHandler.RegisterRoutes(e)
+	jwksHandler.RegisterRoutes(e)
+	oauthHandler.RegisterRoutes(e)
 	// userHandler.RegisterRoutes(e)
 }
@@ -0,0 +1,29 @@
+package apikey
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ApiKey is a long lived credential of a user for integrations that cannot log in,
+// e.g. cron jobs. Only the hash of the key is stored, the key itself is shown once.
+type ApiKey struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Name        string             `bson:"name" json:"name"`
+	Prefix      string             `bson:"prefix" json:"prefix"` // start of the key, to tell keys apart
+	KeyHash     string             `bson:"key_hash" json:"-"`
+	Permissions []string           `bson:"permissions" json:"permissions"` // subset of the owner's permissions
+	ExpiresAt   time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	LastUsedAt  time.Time          `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+type ApiKeys []ApiKey
+
+// IsExpired reports whether the key had an expiry and it has passed.
+func (k *ApiKey) IsExpired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && k.ExpiresAt.Before(now)
+}
@@ -0,0 +1,12 @@
+package apikey
+
+// ApiKeyRepository defines the interface for api key data access operations.
+type ApiKeyRepository interface {
+	Create(key *ApiKey) error
+	FindByID(id any) (*ApiKey, error)
+	FindByHash(hash string) (*ApiKey, error)
+	FindByUserID(userID any) (ApiKeys, error)
+	Update(key *ApiKey) error
+	Delete(id any) error
+	DeleteByUserID(userID any) error
+}
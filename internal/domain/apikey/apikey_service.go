@@ -0,0 +1,17 @@
+package apikey
+
+import "github.com/yasinsaee/go-user-service/pkg/jwt"
+
+// ApiKeyService defines business logic operations related to api keys.
+type ApiKeyService interface {
+	// Create stores the key and returns it in plain text, which is not stored.
+	// Its permissions must all be granted to the owner.
+	Create(key *ApiKey) (string, error)
+	GetByID(id any) (*ApiKey, error)
+	ListByUser(userID any) (ApiKeys, error)
+	Delete(id any) error
+
+	// AuthenticateKey resolves a plain key into claims shaped like the owner's access token,
+	// carrying the key's permissions that the owner still has.
+	AuthenticateKey(key string) (*jwt.JWTClaims, error)
+}
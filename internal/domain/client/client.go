@@ -0,0 +1,77 @@
+package client
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Client is a registered application, e.g. a backend service, that authenticates with its own credentials.
+type Client struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClientID    string             `bson:"client_id" json:"client_id"`
+	SecretHash  string             `bson:"secret_hash" json:"-"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Scopes      []string           `bson:"scopes" json:"scopes"` // permissions the client may request
+	// where the authorization endpoint may send users back to, compared exactly
+	RedirectURIs []string `bson:"redirect_uris,omitempty" json:"redirect_uris,omitempty"`
+	// public clients, e.g. mobile and browser apps, cannot keep a secret and redeem codes with PKCE alone
+	Public    bool      `bson:"public" json:"public"`
+	IsActive  bool      `bson:"is_active" json:"is_active"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type Clients []Client
+
+// GrantScopes returns the scopes a token request gets, every allowed scope when none
+// is requested. It fails when a requested scope is not allowed for the client.
+func (c *Client) GrantScopes(requested []string) ([]string, bool) {
+	if len(requested) == 0 {
+		return c.Scopes, true
+	}
+	for _, scope := range requested {
+		if !slices.Contains(c.Scopes, scope) {
+			return nil, false
+		}
+	}
+	return requested, true
+}
+
+// AllowsRedirectURI reports whether uri is one of the registered redirect URIs.
+func (c *Client) AllowsRedirectURI(uri string) bool {
+	return slices.Contains(c.RedirectURIs, uri)
+}
+
+// ValidateRedirectURIs checks redirect URIs before they are registered. They must be
+// absolute without a fragment and use https, http on the loopback interface, or a
+// private-use scheme like com.example.app for native apps (RFC 8252).
+func ValidateRedirectURIs(uris []string) error {
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		if err != nil || !u.IsAbs() {
+			return errors.New("redirect uri " + uri + " is not an absolute uri")
+		}
+		if u.Fragment != "" || strings.Contains(uri, "#") {
+			return errors.New("redirect uri " + uri + " must not have a fragment")
+		}
+
+		switch u.Scheme {
+		case "https":
+		case "http":
+			if host := u.Hostname(); host != "localhost" && host != "127.0.0.1" && host != "::1" {
+				return errors.New("redirect uri " + uri + " must use https")
+			}
+		default:
+			if !strings.Contains(u.Scheme, ".") {
+				return errors.New("redirect uri " + uri + " must use https or a reverse domain scheme")
+			}
+		}
+	}
+	return nil
+}
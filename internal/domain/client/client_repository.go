@@ -0,0 +1,11 @@
+package client
+
+// ClientRepository defines the interface for client data access operations.
+type ClientRepository interface {
+	Create(client *Client) error
+	FindByID(id any) (*Client, error)
+	FindByClientID(clientID string) (*Client, error)
+	Update(client *Client) error
+	Delete(id any) error
+	List() (Clients, error)
+}
@@ -0,0 +1,15 @@
+package client
+
+// ClientService defines business logic operations related to registered clients.
+type ClientService interface {
+	// Register creates a client and returns its plain secret, which is not stored
+	Register(client *Client) (secret string, err error)
+	GetByID(id any) (*Client, error)
+	GetByClientID(clientID string) (*Client, error)
+	Update(client *Client) error
+	Delete(id any) error
+	ListAll() (Clients, error)
+
+	// Authenticate checks the credentials of an active client
+	Authenticate(clientID, secret string) (*Client, error)
+}
@@ -0,0 +1,25 @@
+package client
+
+import "testing"
+
+func TestValidateRedirectURIs(t *testing.T) {
+	tests := []struct {
+		uri   string
+		valid bool
+	}{
+		{"https://app.example.com/callback", true},
+		{"http://localhost:3000/callback", true},
+		{"http://127.0.0.1/callback", true},
+		{"com.example.app:/oauth", true},
+		{"http://app.example.com/callback", false},
+		{"https://app.example.com/callback#frag", false},
+		{"/callback", false},
+		{"javascript:alert(1)", false},
+	}
+	for _, tt := range tests {
+		err := ValidateRedirectURIs([]string{tt.uri})
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateRedirectURIs(%q) error = %v, want valid %v", tt.uri, err, tt.valid)
+		}
+	}
+}
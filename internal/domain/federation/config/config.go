@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"strings"
+
+	"github.com/yasinsaee/go-user-service/pkg/oidc"
+)
+
+type ProviderConfig struct {
+	oidc.Config
+	AutoRegister bool // create a user for identities without an account
+}
+
+// LoadProviders reads the providers named in OIDC_PROVIDERS, e.g. "google,keycloak",
+// each one from OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET, _SCOPES and _AUTO_REGISTER.
+func LoadProviders() []ProviderConfig {
+	var providers []ProviderConfig
+	for _, name := range strings.Split(getEnv("OIDC_PROVIDERS", ""), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers = append(providers, ProviderConfig{
+			Config: oidc.Config{
+				Name:         name,
+				Issuer:       getEnv(prefix+"ISSUER", ""),
+				ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+				ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+				Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid email profile")),
+			},
+			AutoRegister: getEnv(prefix+"AUTO_REGISTER", "true") == "true",
+		})
+	}
+	return providers
+}
+
+func getEnv(key, defaultVal string) string {
+	if val, ok := os.LookupEnv(key); ok {
+		return val
+	}
+	return defaultVal
+}
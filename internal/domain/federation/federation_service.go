@@ -0,0 +1,30 @@
+package federation
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+)
+
+var (
+	ErrUnknownProvider  = errors.New("unknown identity provider")
+	ErrInvalidState     = errors.New("login state is invalid or expired")
+	ErrEmailNotVerified = errors.New("the identity provider did not verify the email address")
+	ErrNoAccount        = errors.New("no account is linked to this identity")
+	ErrLoginDenied      = errors.New("the sign in at the identity provider was cancelled or failed")
+)
+
+// FederationService signs users in at upstream OpenID Connect providers.
+type FederationService interface {
+	// Providers lists the names of the configured providers
+	Providers() []string
+
+	// AuthURL starts a login at the provider, state is filled in and stored until the callback
+	AuthURL(ctx context.Context, provider string, state *LoginState) (string, error)
+
+	// Callback takes the state, redeems the code at its provider and returns the local user
+	// of the verified identity, linking it by verified email or provisioning a new user.
+	// The state is returned whenever it was found, also on failure.
+	Callback(ctx context.Context, stateID, code string) (*user.User, *LoginState, error)
+}
@@ -0,0 +1,17 @@
+package federation
+
+import (
+	"net/url"
+	"time"
+)
+
+// LoginState carries a login from the redirect to the upstream provider over to its callback.
+type LoginState struct {
+	ID           string     `json:"-"` // the state parameter sent to the provider
+	Provider     string     `json:"provider"`
+	RedirectURI  string     `json:"redirect_uri"` // our callback, the code is bound to it
+	CodeVerifier string     `json:"code_verifier"`
+	Nonce        string     `json:"nonce"`
+	Request      url.Values `json:"request"` // our own authorization request to resume afterwards
+	ExpiresAt    time.Time  `json:"expires_at"`
+}
@@ -0,0 +1,11 @@
+package federation
+
+// LoginStateStore keeps pending upstream logins until their callback or expiry.
+type LoginStateStore interface {
+	// Save stores the state until its ExpiresAt
+	Save(state *LoginState) error
+
+	// Take returns the state and removes it so a callback is accepted only once.
+	// It returns nil when the state does not exist or was already taken.
+	Take(id string) (*LoginState, error)
+}
@@ -0,0 +1,13 @@
+package mfa
+
+import "time"
+
+// AttemptStore counts attempts per user, a six digit code must not be guessable.
+type AttemptStore interface {
+	// Attempt counts an attempt before its code is checked and returns the attempts of
+	// the user in the current window, the window restarts at every attempt
+	Attempt(userID string, window time.Duration) (int, error)
+
+	// Reset forgets the attempts after a successful one
+	Reset(userID string) error
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+type MFAConfig struct {
+	Issuer           string        // shown by authenticator apps next to the account
+	TrustedDeviceTTL time.Duration // how long a trusted device skips the second factor, 0 disables trusting devices
+}
+
+func LoadMFAConfig() MFAConfig {
+	days, _ := strconv.Atoi(getEnv("MFA_TRUSTED_DEVICE_DAYS", "30"))
+	return MFAConfig{
+		Issuer:           getEnv("MFA_ISSUER", "go-user-service"),
+		TrustedDeviceTTL: time.Duration(max(days, 0)) * 24 * time.Hour,
+	}
+}
+
+func getEnv(key, defaultVal string) string {
+	if val, ok := os.LookupEnv(key); ok {
+		return val
+	}
+	return defaultVal
+}
@@ -0,0 +1,45 @@
+package mfa
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+)
+
+var (
+	ErrInvalidCode         = errors.New("invalid authentication code")
+	ErrNotEnrolled         = errors.New("two-factor authentication is not enrolled")
+	ErrAlreadyEnabled      = errors.New("two-factor authentication is already enabled")
+	ErrTooManyAttempts     = errors.New("too many failed attempts, try again later")
+	ErrNoPendingEnrollment = errors.New("no enrollment to confirm, enroll first")
+	ErrTrustDisabled       = errors.New("trusted devices are disabled")
+	ErrDeviceNotFound      = errors.New("trusted device not found")
+)
+
+// MFAService manages the TOTP second factor of users and checks their codes.
+type MFAService interface {
+	// EnrollTOTP starts an enrollment and returns the new secret and its otpauth:// URI.
+	// The secret is pending until ConfirmTOTP proves the app has it.
+	EnrollTOTP(u *user.User) (secret string, uri string, err error)
+	// ConfirmTOTP enables the second factor with a first code and returns the recovery codes,
+	// they are shown once and only their hashes are stored.
+	ConfirmTOTP(u *user.User, code string) ([]string, error)
+	// Verify accepts a TOTP code or an unused recovery code, which is then used up.
+	Verify(u *user.User, code string) error
+	// Disable turns the second factor off after verifying a code.
+	Disable(u *user.User, code string) error
+	// RegenerateRecoveryCodes replaces the recovery codes after verifying a code.
+	RegenerateRecoveryCodes(u *user.User, code string) ([]string, error)
+
+	// TrustDevice remembers the device the user just passed the second factor on
+	// and returns its signed token.
+	TrustDevice(u *user.User, device *TrustedDevice) (string, error)
+	// IsTrustedDevice reports whether token belongs to a live trusted device of the user.
+	IsTrustedDevice(u *user.User, token string) bool
+	ListTrustedDevices(u *user.User) (TrustedDevices, error)
+	// RevokeTrustedDevice forgets one device of the user, or all of them when id is empty.
+	RevokeTrustedDevice(u *user.User, id string) error
+	// TrustedDeviceTTL is how long a device is trusted, 0 when devices cannot be trusted.
+	TrustedDeviceTTL() time.Duration
+}
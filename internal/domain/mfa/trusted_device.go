@@ -0,0 +1,35 @@
+package mfa
+
+import "time"
+
+// TrustedDevice is a device on which the user passed the second factor and asked to be
+// remembered. Logins from it skip the second factor until it expires or is revoked.
+type TrustedDevice struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	DeviceName string    `json:"device_name,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	IP         string    `json:"ip,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type TrustedDevices []TrustedDevice
+
+// TrustedDeviceStore keeps the trusted devices, a device token is honored only while its record exists.
+type TrustedDeviceStore interface {
+	// Save stores the device until it expires
+	Save(device *TrustedDevice) error
+
+	// Get returns a device of the user, nil when it does not exist
+	Get(userID string, deviceID string) (*TrustedDevice, error)
+
+	// List returns the live devices of the user
+	List(userID string) (TrustedDevices, error)
+
+	// Delete forgets a device of the user
+	Delete(userID string, deviceID string) error
+
+	// DeleteAll forgets every device of the user
+	DeleteAll(userID string) error
+}
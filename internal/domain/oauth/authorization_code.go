@@ -0,0 +1,40 @@
+package oauth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+)
+
+// CodeChallengeS256 is the only PKCE method accepted, plain would leak the verifier with the code.
+const CodeChallengeS256 = "S256"
+
+// AuthorizationCode is handed to the client through the user's browser once the
+// user approved it, and redeemed once at the token endpoint for tokens.
+type AuthorizationCode struct {
+	Code                string    `json:"-"`
+	ClientID            string    `json:"client_id"`
+	UserID              string    `json:"user_id"`
+	RedirectURI         string    `json:"redirect_uri"`
+	Scopes              []string  `json:"scopes"`
+	CodeChallenge       string    `json:"code_challenge"`
+	CodeChallengeMethod string    `json:"code_challenge_method"`
+	Nonce               string    `json:"nonce,omitempty"` // OpenID Connect nonce, echoed in the ID token
+	ExpiresAt           time.Time `json:"expires_at"`
+
+	// how and when the user signed in on the consent page, carried into the tokens
+	Authentication jwt.Authentication `json:"authentication"`
+}
+
+// VerifyChallenge checks the PKCE code verifier against the challenge sent with the authorization request (RFC 7636).
+func (c *AuthorizationCode) VerifyChallenge(verifier string) bool {
+	if c.CodeChallengeMethod != CodeChallengeS256 || verifier == "" {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(challenge), []byte(c.CodeChallenge)) == 1
+}
@@ -0,0 +1,11 @@
+package oauth
+
+// AuthorizationCodeStore keeps issued authorization codes until they are redeemed or expire.
+type AuthorizationCodeStore interface {
+	// Save stores the code until its ExpiresAt
+	Save(code *AuthorizationCode) error
+
+	// Take returns the code and removes it, so it can be redeemed only once.
+	// It returns nil when the code does not exist or was already taken.
+	Take(code string) (*AuthorizationCode, error)
+}
@@ -0,0 +1,27 @@
+package oauth
+
+import "testing"
+
+func TestVerifyChallenge(t *testing.T) {
+	// example of RFC 7636 appendix B
+	code := &AuthorizationCode{
+		CodeChallenge:       "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		CodeChallengeMethod: CodeChallengeS256,
+	}
+
+	if !code.VerifyChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk") {
+		t.Error("VerifyChallenge() rejected the matching verifier")
+	}
+	if code.VerifyChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXl") {
+		t.Error("VerifyChallenge() accepted another verifier")
+	}
+	if code.VerifyChallenge("") {
+		t.Error("VerifyChallenge() accepted an empty verifier")
+	}
+
+	code.CodeChallengeMethod = "plain"
+	code.CodeChallenge = "verifier"
+	if code.VerifyChallenge("verifier") {
+		t.Error("VerifyChallenge() accepted the plain method")
+	}
+}
@@ -0,0 +1,16 @@
+package oauth
+
+import "errors"
+
+// ErrInvalidGrant is returned for any code that cannot be redeemed, without telling why.
+var ErrInvalidGrant = errors.New("authorization code is invalid, expired or was issued to another client")
+
+// AuthorizationService defines the authorization code grant of the OAuth 2.0 server.
+type AuthorizationService interface {
+	// IssueCode fills in and stores a new code for an approved authorization request
+	IssueCode(code *AuthorizationCode) error
+
+	// RedeemCode consumes the code, it must have been issued to the client for the
+	// same redirect uri and match the PKCE verifier
+	RedeemCode(code, clientID, redirectURI, verifier string) (*AuthorizationCode, error)
+}
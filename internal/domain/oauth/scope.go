@@ -0,0 +1,27 @@
+package oauth
+
+import "slices"
+
+// OpenID Connect scopes, any client may request them. Every other scope is a permission
+// name and must be allowed for the client.
+const (
+	ScopeOpenID  = "openid"  // ask for an ID token
+	ScopeProfile = "profile" // name, username, picture and updated_at
+	ScopeEmail   = "email"
+	ScopePhone   = "phone"
+)
+
+// IdentityScopes lists the OpenID Connect scopes.
+var IdentityScopes = []string{ScopeOpenID, ScopeProfile, ScopeEmail, ScopePhone}
+
+// SplitScopes separates the OpenID Connect scopes from the permission scopes.
+func SplitScopes(scopes []string) (identity []string, permissions []string) {
+	for _, scope := range scopes {
+		if slices.Contains(IdentityScopes, scope) {
+			identity = append(identity, scope)
+		} else {
+			permissions = append(permissions, scope)
+		}
+	}
+	return
+}
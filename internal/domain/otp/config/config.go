@@ -12,6 +12,8 @@ type OTPConfig struct {
 	TTL               time.Duration
 	RateLimit         int
 	MaxOTPPerReceiver int
+	MaxAttempts       int
+	LoginAutoRegister bool
 }
 
 func LoadOTPConfig() OTPConfig {
@@ -20,6 +22,8 @@ func LoadOTPConfig() OTPConfig {
 	ttl := time.Duration(getEnvInt("OTP_TTL_SECONDS", 120)) * time.Second
 	rateLimit := getEnvInt("OTP_RATE_LIMIT", 5)
 	maxOTPPerReceiver := getEnvInt("MAX_OTP_PER_RECEIVER", 0)
+	maxAttempts := getEnvInt("OTP_MAX_ATTEMPTS", 5)
+	loginAutoRegister := getEnv("OTP_LOGIN_AUTO_REGISTER", "false") == "true"
 
 	return OTPConfig{
 		Length:            length,
@@ -27,6 +31,8 @@ func LoadOTPConfig() OTPConfig {
 		TTL:               ttl,
 		RateLimit:         rateLimit,
 		MaxOTPPerReceiver: maxOTPPerReceiver,
+		MaxAttempts:       maxAttempts,
+		LoginAutoRegister: loginAutoRegister,
 	}
 }
 
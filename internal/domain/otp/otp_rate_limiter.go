@@ -5,4 +5,9 @@ import "time"
 type OTPRateLimiter interface {
 	CanSend(receiver string) (bool, error)
 	MarkSend(receiver string, ttl time.Duration) error
+
+	// validations, a code may only be guessed a few times. Attempts are counted before
+	// the code is looked up, so parallel guesses cannot all pass the limit
+	AttemptVerify(receiver string, ttl time.Duration) (bool, error)
+	ResetVerify(receiver string) error
 }
\ No newline at end of file
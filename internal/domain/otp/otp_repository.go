@@ -11,6 +11,7 @@ type OTPRepository interface {
 	List() (Otps, error)
 	FindByReceiverAndCode(receiver, code string) (*Otp, error)
 	FindByReceiverAndCodeAndType(receiver, code string, otpType OtpType) (*Otp, error)
+	MarkUsed(id any) (bool, error) // false when the code was used already
 	ListByType(otpType OtpType) (Otps, error)
 	DeleteExpiredOtps() error
 	Count(q bson.M) (int, error)
@@ -23,6 +23,8 @@ type OTPService interface {
 	// Rate limiting
 	CanSend(receiver string) (bool, error)
 	MarkSend(receiver string) error
+	AttemptVerify(receiver string) (bool, error)
+	ResetVerify(receiver string) error
 
 	// Hard limit Check
 	CheckHardLimit(receiver string, otpType OtpType) (bool, error)
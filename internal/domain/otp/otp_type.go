@@ -6,4 +6,5 @@ const (
 	OtpTypeForgotPassword OtpType = iota
 	OtpTypeRegister
 	OtpTypeVerifyEmail
+	OtpTypeLogin
 )
\ No newline at end of file
@@ -0,0 +1,15 @@
+package passkey
+
+import (
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+// Ceremony is a WebAuthn registration or login waiting for the authenticator's response.
+type Ceremony struct {
+	ID        string               `json:"-"`
+	UserID    string               `json:"user_id,omitempty"` // the registering user, empty for a login
+	Session   webauthn.SessionData `json:"session"`           // challenge and expectations of the response
+	ExpiresAt time.Time            `json:"expires_at"`
+}
@@ -0,0 +1,11 @@
+package passkey
+
+// CeremonyStore keeps ceremonies until they are finished or expire.
+type CeremonyStore interface {
+	// Save stores the ceremony until its ExpiresAt
+	Save(c *Ceremony) error
+
+	// Take returns the ceremony and removes it so each challenge is answered only once.
+	// It returns nil when the ceremony does not exist or was already taken.
+	Take(id string) (*Ceremony, error)
+}
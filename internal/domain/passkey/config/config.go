@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// PasskeyConfig describes this service as a WebAuthn relying party.
+type PasskeyConfig struct {
+	RPID          string   // domain passkeys are bound to, e.g. example.com
+	RPDisplayName string   // shown by the authenticator
+	RPOrigins     []string // origins of the pages running the ceremonies, e.g. https://app.example.com
+}
+
+func LoadPasskeyConfig() PasskeyConfig {
+	var origins []string
+	for _, origin := range strings.Split(getEnv("WEBAUTHN_RP_ORIGINS", "http://localhost:8080"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+
+	return PasskeyConfig{
+		RPID:          getEnv("WEBAUTHN_RP_ID", "localhost"),
+		RPDisplayName: getEnv("WEBAUTHN_RP_NAME", "go-user-service"),
+		RPOrigins:     origins,
+	}
+}
+
+func getEnv(key, defaultVal string) string {
+	if val, ok := os.LookupEnv(key); ok {
+		return val
+	}
+	return defaultVal
+}
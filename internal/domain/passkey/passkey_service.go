@@ -0,0 +1,32 @@
+package passkey
+
+import (
+	"errors"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+)
+
+var (
+	ErrInvalidCeremony = errors.New("passkey ceremony is unknown or expired, start again")
+	ErrInvalidPasskey  = errors.New("passkey could not be verified")
+	ErrCloneDetected   = errors.New("passkey may have been cloned, it can no longer be used")
+	ErrPasskeyNotFound = errors.New("passkey not found")
+)
+
+// PasskeyService runs the WebAuthn ceremonies. Options and responses are the JSON
+// passed to and returned by navigator.credentials in the browser.
+type PasskeyService interface {
+	// BeginRegistration returns the creation options for a new passkey of the user.
+	BeginRegistration(u *user.User) (options []byte, ceremonyID string, err error)
+	// FinishRegistration verifies the authenticator's attestation and stores the passkey.
+	FinishRegistration(u *user.User, ceremonyID, nickname string, response []byte) (*user.Passkey, error)
+
+	// BeginLogin returns the request options of a login, any passkey of any user may answer it.
+	BeginLogin() (options []byte, ceremonyID string, err error)
+	// FinishLogin verifies the assertion and returns the owner of the passkey.
+	// A sign count that did not increase marks the passkey as cloned and refuses it.
+	FinishLogin(ceremonyID string, response []byte) (*user.User, error)
+
+	// DeletePasskey removes a passkey of the user by its base64url id.
+	DeletePasskey(u *user.User, id string) error
+}
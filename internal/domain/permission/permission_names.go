@@ -0,0 +1,36 @@
+package permission
+
+// Names of the permissions the service checks itself. They are granted like any
+// other permission, by creating them and adding them to a role.
+const (
+	UserUpdate         = "user.update"
+	UserUpdatePassword = "user.update_password" // set a password without knowing the current one
+	UserAssignRoles    = "user.assign_roles"    // pick roles when registering a user
+	LogoutAllUsers     = "user.logout_all"      // log out any user, not only yourself
+	UserImport         = "user.import"          // create users with password hashes of another application
+	UserSetActive      = "user.set_active"      // deactivate users and activate them again
+
+	RoleCreate = "role.create"
+	RoleRead   = "role.read"
+	RoleUpdate = "role.update"
+	RoleDelete = "role.delete"
+
+	PermissionCreate = "permission.create"
+	PermissionRead   = "permission.read"
+	PermissionUpdate = "permission.update"
+	PermissionDelete = "permission.delete"
+
+	OTPCreate = "otp.create"
+	OTPRead   = "otp.read"
+	OTPUpdate = "otp.update"
+	OTPDelete = "otp.delete"
+
+	ClientCreate = "client.create"
+	ClientRead   = "client.read"
+	ClientUpdate = "client.update"
+	ClientDelete = "client.delete"
+
+	TokenRevoke = "token.revoke" // revoke tokens of any user
+
+	ApiKeyManage = "apikey.manage" // manage api keys of any user, not only your own
+)
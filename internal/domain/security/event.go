@@ -0,0 +1,23 @@
+package security
+
+import "time"
+
+type EventType string
+
+const (
+	// EventRefreshTokenReuse is raised when a rotated refresh token is presented again,
+	// the token was most likely stolen so its whole family has been revoked.
+	EventRefreshTokenReuse EventType = "refresh_token_reuse"
+
+	// EventPasskeyCloned is raised when a passkey's sign count goes backwards, two copies
+	// of its private key may exist so the passkey is refused from then on.
+	EventPasskeyCloned EventType = "passkey_cloned"
+)
+
+// Event is a security relevant occurrence that operators should be able to alert on.
+type Event struct {
+	Type       EventType         `json:"type"`
+	UserID     string            `json:"user_id,omitempty"`
+	Details    map[string]string `json:"details,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
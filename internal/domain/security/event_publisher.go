@@ -0,0 +1,5 @@
+package security
+
+type EventPublisher interface {
+	Publish(event Event) error
+}
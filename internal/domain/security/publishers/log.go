@@ -0,0 +1,25 @@
+package publishers
+
+import (
+	"encoding/json"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/security"
+	"github.com/yasinsaee/go-user-service/pkg/logger"
+)
+
+// LogEventPublisher writes security events to the service log as JSON,
+// so they can be picked up by whatever ships the logs.
+type LogEventPublisher struct{}
+
+func NewLogEventPublisher() *LogEventPublisher {
+	return &LogEventPublisher{}
+}
+
+func (p *LogEventPublisher) Publish(event security.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	logger.Warn("security event: ", string(data))
+	return nil
+}
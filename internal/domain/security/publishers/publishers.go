@@ -0,0 +1,16 @@
+package publishers
+
+import (
+	"github.com/yasinsaee/go-user-service/internal/app/config"
+	"github.com/yasinsaee/go-user-service/internal/domain/security"
+)
+
+func NewEventPublisher() security.EventPublisher {
+	publisherType := config.GetEnv("SECURITY_EVENT_PUBLISHER", "LOG")
+
+	switch publisherType {
+	case "LOG":
+		return NewLogEventPublisher()
+	}
+	return NewLogEventPublisher()
+}
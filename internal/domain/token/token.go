@@ -0,0 +1,36 @@
+package token
+
+import (
+	"time"
+
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+)
+
+// Introspection describes a token as seen by this service (RFC 7662).
+// Only Active is meaningful when the token is not active.
+type Introspection struct {
+	Active    bool
+	TokenType jwt.TokenType
+	Principal jwt.PrincipalType
+	ClientID  string
+	Subject   string
+	Username  string
+	Roles     []string
+	Access    []string
+	TokenID   string
+	Issuer    string
+	Audience  []string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+
+	// how the user signed in, empty for client tokens
+	Authentication jwt.Authentication
+}
+
+// TypeFromHint maps an RFC 7662 token_type_hint to a token type, access tokens are the default.
+func TypeFromHint(hint string) jwt.TokenType {
+	if hint == "refresh_token" {
+		return jwt.TokenTypeRefresh
+	}
+	return jwt.TokenTypeAccess
+}
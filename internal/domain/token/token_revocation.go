@@ -0,0 +1,24 @@
+package token
+
+import "time"
+
+// RevocationStore keeps revoked tokens until they would have expired anyway.
+type RevocationStore interface {
+	// Revoke a single token by its jti until it expires
+	Revoke(tokenID string, expiresAt time.Time) error
+
+	// Check if the token with this jti has been revoked
+	IsRevoked(tokenID string) (bool, error)
+
+	// Revoke every token of the user issued before the given time
+	RevokeBefore(userID string, before time.Time) error
+
+	// Time before which tokens of the user are revoked, zero if none
+	RevokedBefore(userID string) (time.Time, error)
+
+	// Revoke every token issued for the session
+	RevokeSession(sessionID string) error
+
+	// Check if the session has been revoked
+	IsSessionRevoked(sessionID string) (bool, error)
+}
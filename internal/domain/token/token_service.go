@@ -0,0 +1,26 @@
+package token
+
+import (
+	"time"
+
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+)
+
+// TokenService defines business logic operations related to issued tokens.
+type TokenService interface {
+	// Revoke a signed access or refresh token before it expires
+	RevokeToken(token string) error
+
+	// Revoke every token the user got before the given time, e.g. after a password change.
+	// Client tokens are revoked the same way by client id, their subject.
+	RevokeUserTokens(userID string, before time.Time) error
+
+	// Revoke the access tokens issued for a session, its refresh tokens are dropped with the session itself
+	RevokeSession(sessionID string) error
+
+	// Introspect reports whether an access or refresh token is active, trying hint first
+	Introspect(token string, hint jwt.TokenType) (*Introspection, error)
+
+	// IsRevoked implements jwt.RevocationList
+	IsRevoked(claims jwt.RegisteredClaims) (bool, error)
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Character classes a password policy may require.
+const (
+	ClassLower  = "lower"
+	ClassUpper  = "upper"
+	ClassDigit  = "digit"
+	ClassSymbol = "symbol"
+)
+
+type PasswordPolicyConfig struct {
+	MinLength       int      // in characters
+	MaxLength       int      // in bytes, keep it at 72 when hashing with bcrypt
+	RequiredClasses []string // each one must occur at least once
+	BreachedDir     string   // Pwned Passwords range files, the check is skipped when empty
+
+	HistorySize        int      // passwords that may not be reused, the current one included
+	HistoryExemptRoles []string // names of roles whose users may reuse passwords
+
+	MaxAge      time.Duration // after which a password expires, 0 when passwords do not expire
+	MaxAgeRoles []string      // names of roles whose passwords expire, every user's when empty
+}
+
+// LoadPasswordPolicyConfig reads PASSWORD_MIN_LENGTH, PASSWORD_MAX_LENGTH, PASSWORD_REQUIRED_CLASSES,
+// a comma separated subset of lower, upper, digit and symbol, PASSWORD_BREACHED_DIR, PASSWORD_HISTORY,
+// PASSWORD_HISTORY_EXEMPT_ROLES, PASSWORD_MAX_AGE_DAYS and PASSWORD_MAX_AGE_ROLES. Roles are comma
+// separated lists of role names.
+func LoadPasswordPolicyConfig() PasswordPolicyConfig {
+	var classes []string
+	for _, class := range strings.Split(getEnv("PASSWORD_REQUIRED_CLASSES", ""), ",") {
+		switch class = strings.TrimSpace(class); class {
+		case ClassLower, ClassUpper, ClassDigit, ClassSymbol:
+			classes = append(classes, class)
+		}
+	}
+
+	return PasswordPolicyConfig{
+		MinLength:       getEnvInt("PASSWORD_MIN_LENGTH", 8),
+		MaxLength:       getEnvInt("PASSWORD_MAX_LENGTH", 128),
+		RequiredClasses: classes,
+		BreachedDir:     getEnv("PASSWORD_BREACHED_DIR", ""),
+
+		HistorySize:        getEnvInt("PASSWORD_HISTORY", 5),
+		HistoryExemptRoles: roleNames("PASSWORD_HISTORY_EXEMPT_ROLES"),
+
+		MaxAge:      time.Duration(getEnvInt("PASSWORD_MAX_AGE_DAYS", 0)) * 24 * time.Hour,
+		MaxAgeRoles: roleNames("PASSWORD_MAX_AGE_ROLES"),
+	}
+}
+
+// roleNames reads a comma separated list of role names.
+func roleNames(key string) []string {
+	var names []string
+	for _, name := range strings.Split(getEnv(key, ""), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Algorithms of new password hashes.
+const (
+	HashArgon2id = "argon2id"
+	HashBcrypt   = "bcrypt"
+)
+
+type PasswordHashConfig struct {
+	Algorithm           string // of new hashes, hashes of the other one are still verified
+	Argon2Memory        int    // in KiB
+	Argon2Iterations    int
+	Argon2Parallelism   int
+	BcryptCost          int
+	PepperFile          string   // secret mixed into Argon2id hashes, none when empty
+	PreviousPepperFiles []string // peppers replaced by PepperFile, their hashes still verify
+}
+
+// LoadPasswordHashConfig reads PASSWORD_HASH_ALGORITHM, PASSWORD_ARGON2_MEMORY_KIB, PASSWORD_ARGON2_ITERATIONS,
+// PASSWORD_ARGON2_PARALLELISM, PASSWORD_BCRYPT_COST, PASSWORD_PEPPER_FILE and PASSWORD_PREVIOUS_PEPPER_FILES,
+// a comma separated list of files.
+func LoadPasswordHashConfig() PasswordHashConfig {
+	var previous []string
+	for _, file := range strings.Split(getEnv("PASSWORD_PREVIOUS_PEPPER_FILES", ""), ",") {
+		if file = strings.TrimSpace(file); file != "" {
+			previous = append(previous, file)
+		}
+	}
+
+	return PasswordHashConfig{
+		Algorithm:           getEnv("PASSWORD_HASH_ALGORITHM", HashArgon2id),
+		Argon2Memory:        getEnvInt("PASSWORD_ARGON2_MEMORY_KIB", 19*1024),
+		Argon2Iterations:    getEnvInt("PASSWORD_ARGON2_ITERATIONS", 2),
+		Argon2Parallelism:   getEnvInt("PASSWORD_ARGON2_PARALLELISM", 1),
+		BcryptCost:          getEnvInt("PASSWORD_BCRYPT_COST", 12),
+		PepperFile:          getEnv("PASSWORD_PEPPER_FILE", ""),
+		PreviousPepperFiles: previous,
+	}
+}
+
+func getEnv(key, defaultVal string) string {
+	if val, ok := os.LookupEnv(key); ok {
+		return val
+	}
+	return defaultVal
+}
+
+func getEnvInt(key string, defaultVal int) int {
+	if val, ok := os.LookupEnv(key); ok {
+		if i, err := strconv.Atoi(val); err == nil {
+			return i
+		}
+	}
+	return defaultVal
+}
@@ -0,0 +1,9 @@
+package user
+
+// PasswordHasher hashes the passwords stored in User.Password.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify checks password against hash. rehash tells that the hash uses an outdated
+	// algorithm or cost and should be replaced by a new hash of the password.
+	Verify(password, hash string) (ok, rehash bool)
+}
@@ -0,0 +1,57 @@
+package user
+
+import "strings"
+
+// Rules of the password policy, reported as the reason of each violation.
+const (
+	PasswordTooShort             = "PASSWORD_TOO_SHORT"
+	PasswordTooLong              = "PASSWORD_TOO_LONG"
+	PasswordMissingClass         = "PASSWORD_MISSING_CHARACTER_CLASS"
+	PasswordContainsPersonalInfo = "PASSWORD_CONTAINS_PERSONAL_INFO"
+	PasswordBreached             = "PASSWORD_BREACHED"
+	PasswordReused               = "PASSWORD_REUSED"
+)
+
+// Reasons a user has to change the password before a password login grants access.
+const (
+	PasswordExpired        = "PASSWORD_EXPIRED"
+	PasswordChangeRequired = "PASSWORD_CHANGE_REQUIRED" // flagged by an admin
+)
+
+// PasswordViolation is one rule a password failed.
+type PasswordViolation struct {
+	Rule        string
+	Description string
+}
+
+// PasswordPolicyError lists every rule a password failed, not only the first one.
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	descriptions := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		descriptions[i] = v.Description
+	}
+	return "password does not meet the policy: " + strings.Join(descriptions, "; ")
+}
+
+// PasswordPolicy checks a password before it is set for a user.
+type PasswordPolicy interface {
+	// Validate returns a *PasswordPolicyError when the password fails any rule. The user's
+	// username, phone number and email must not be part of it, nor may a stored user's
+	// current or remembered passwords be reused.
+	Validate(password string, u *User) error
+	// ChangeReason returns why the user has to change the password before signing in with
+	// it, PasswordChangeRequired or PasswordExpired, or "" when the password is fine.
+	ChangeReason(u *User) string
+	// HistorySize is how many passwords of a user are remembered, the current one included,
+	// 0 when passwords may be reused.
+	HistorySize() int
+}
+
+// BreachedPasswords tells whether a password is known from a data breach.
+type BreachedPasswords interface {
+	Contains(password string) (bool, error)
+}
@@ -0,0 +1,17 @@
+package user
+
+import "time"
+
+// Session is one login of a user on a device. Its ID is the refresh token
+// family, so it lives as long as the refresh tokens rotated from that login.
+type Session struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	DeviceName  string    `json:"device_name,omitempty"`
+	UserAgent   string    `json:"user_agent,omitempty"`
+	IP          string    `json:"ip,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastRefresh time.Time `json:"last_refresh"`
+}
+
+type Sessions []Session
@@ -20,6 +20,73 @@ type User struct {
 	CreatedAt    time.Time            `bson:"created_at" json:"created_at"`
 	UpdatedAt    time.Time            `bson:"updated_at" json:"updated_at"`
 	LastLogin    time.Time            `bson:"last_login,omitempty" json:"last_login,omitempty"`
+
+	// DeactivatedAt is when the user was deactivated. Users from before were stored with is_active
+	// false without being deactivated, so only both together make a deactivated user, see Active.
+	DeactivatedAt time.Time `bson:"deactivated_at,omitempty" json:"deactivated_at,omitempty"`
+
+	ExternalIdentities []ExternalIdentity `bson:"external_identities,omitempty" json:"external_identities,omitempty"`
+	MFA                MFA                `bson:"mfa,omitempty" json:"-"`
+	Passkeys           []Passkey          `bson:"passkeys,omitempty" json:"-"`
+
+	// PasswordChangedAt is when the password was last set, CreatedAt stands in for users from before.
+	PasswordChangedAt time.Time `bson:"password_changed_at,omitempty" json:"password_changed_at,omitempty"`
+	// MustChangePassword is set by an admin, the next password login only allows changing it.
+	MustChangePassword bool `bson:"must_change_password" json:"must_change_password"`
+
+	// PasswordHistory holds the hashes of the passwords before the current one, newest first.
+	PasswordHistory []string `bson:"password_history,omitempty" json:"-"`
+
+	// LegacyPassword replaces Password for a user imported from another application, until
+	// the first login. It is not omitempty, so the update of that login removes it.
+	LegacyPassword *LegacyPassword `bson:"legacy_password" json:"-"`
+}
+
+// LegacyPassword is a password hash in a foreign format, one of the legacy formats of pkg/password.
+type LegacyPassword struct {
+	Format     string    `bson:"format"`
+	Hash       string    `bson:"hash"`
+	Salt       string    `bson:"salt,omitempty"`
+	ImportedAt time.Time `bson:"imported_at"`
+}
+
+// MFA is the user's second factor, a TOTP authenticator app with recovery codes.
+type MFA struct {
+	Enabled       bool      `bson:"enabled"`
+	Secret        string    `bson:"secret,omitempty"`         // base32 TOTP secret
+	PendingSecret string    `bson:"pending_secret,omitempty"` // enrolled but not confirmed by a first code yet
+	LastStep      int64     `bson:"last_step,omitempty"`      // time step of the last accepted code, codes are single use
+	RecoveryCodes []string  `bson:"recovery_codes,omitempty"` // hashes of the unused recovery codes
+	EnabledAt     time.Time `bson:"enabled_at,omitempty"`
+}
+
+// ExternalIdentity links the user to an account at an upstream identity provider.
+type ExternalIdentity struct {
+	Provider string    `bson:"provider" json:"provider"`
+	Subject  string    `bson:"subject" json:"subject"` // sub claim of the provider's ID tokens
+	LinkedAt time.Time `bson:"linked_at" json:"linked_at"`
+}
+
+// Passkey is a WebAuthn credential the user signs in with instead of a password.
+// Its user handle is the user's id.
+type Passkey struct {
+	ID              []byte    `bson:"id" json:"id"`        // credential id chosen by the authenticator
+	PublicKey       []byte    `bson:"public_key" json:"-"` // COSE encoded
+	AttestationType string    `bson:"attestation_type" json:"-"`
+	AAGUID          []byte    `bson:"aaguid,omitempty" json:"-"`
+	SignCount       uint32    `bson:"sign_count" json:"-"`
+	Transports      []string  `bson:"transports,omitempty" json:"transports,omitempty"`
+	BackupEligible  bool      `bson:"backup_eligible" json:"-"`
+	BackupState     bool      `bson:"backup_state" json:"-"`
+	Nickname        string    `bson:"nickname" json:"nickname"`
+	CloneWarning    bool      `bson:"clone_warning,omitempty" json:"clone_warning,omitempty"` // the sign count went backwards, the passkey is refused
+	CreatedAt       time.Time `bson:"created_at" json:"created_at"`
+	LastUsedAt      time.Time `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+}
+
+// Active reports whether the user may sign in and use the credentials it holds.
+func (u *User) Active() bool {
+	return u.IsActive || u.DeactivatedAt.IsZero()
 }
 
 type Users []User
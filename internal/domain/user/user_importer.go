@@ -0,0 +1,42 @@
+package user
+
+import "io"
+
+// Formats of import files.
+const (
+	ImportJSONL = "jsonl" // one JSON object per line
+	ImportCSV   = "csv"   // a header line naming the columns, then one user per line
+)
+
+// ImportRecord is a user of an import file. JSON keys and CSV columns are named after the json tags.
+type ImportRecord struct {
+	Username     string `json:"username"` // the phone number or email when empty
+	Email        string `json:"email"`
+	PhoneNumber  string `json:"phone_number"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	PasswordHash string `json:"password_hash"`
+	HashFormat   string `json:"hash_format"`
+	Salt         string `json:"salt"`
+}
+
+// ImportFailure is a line of an import file that was skipped.
+type ImportFailure struct {
+	Line     int
+	Username string
+	Reason   string
+}
+
+// ImportResult sums up an import.
+type ImportResult struct {
+	Imported int
+	Failures []ImportFailure
+}
+
+// UserImporter creates users exported from another application, keeping their password hashes.
+type UserImporter interface {
+	// Import reads the users of an import file and creates them, or with dryRun only checks
+	// them. A user that cannot be imported is reported and skipped, the error is returned
+	// for a file that cannot be read at all.
+	Import(r io.Reader, format string, dryRun bool) (*ImportResult, error)
+}
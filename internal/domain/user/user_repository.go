@@ -8,4 +8,10 @@ type UserRepository interface {
 	Update(user *User) error                       // Updates an existing user
 	Delete(id any) error                           // Deletes a user by ID
 	List() (Users, error)
+
+	FindByPhoneNumber(phoneNumber string) (*User, error)            // Retrieves a user by their phone number only
+	FindByExternalIdentity(provider, subject string) (*User, error) // Retrieves the user linked to an upstream account
+
+	ConsumeTOTPStep(id any, step int64) (bool, error)      // Records an accepted TOTP step, false when it or a later one was already used
+	ConsumeRecoveryCode(id any, hash string) (bool, error) // Removes a recovery code, false when it was already used
 }
@@ -1,9 +1,19 @@
 package user
 
+import "errors"
+
+// ErrDeactivated is returned when a deactivated user tries to sign in.
+var ErrDeactivated = errors.New("user is deactivated")
+
 // UserService defines business logic operations related to users.
 type UserService interface {
 	Register(username string, user *User) error
 	Login(username, password string) (*User, error)
+	// LoginWithPhone logs in the owner of a phone number that was verified with an OTP.
+	// Unknown numbers are registered as newUser, or refused when it is nil.
+	LoginWithPhone(phoneNumber string, newUser *User) (*User, error)
+	// VerifyPassword checks the password of a user who is already signed in, e.g. for a step-up.
+	VerifyPassword(user *User, password string) error
 	GetByID(id any) (*User, error)
 	GetByUsername(username string) (*User, error)
 	Update(user *User) error
@@ -11,9 +21,34 @@ type UserService interface {
 	ListAll() (Users, error)
 	ResetPassword(user *User, currentPassword, password, rePassword string) error
 	UpdatePassword(user *User, password, rePassword string) error
+	// PasswordChangeReason returns why the user has to change the password before any login
+	// grants access, "" when it does not have to.
+	PasswordChangeReason(user *User) string
+	// RequirePasswordChange makes the user change the password at the next login.
+	RequirePasswordChange(user *User) error
+	// SetActive activates or deactivates the user. Deactivating logs the user out everywhere
+	// and keeps it from signing in until it is activated again.
+	SetActive(user *User, active bool) error
 
 	//refresh token methods redis-based
-	StoreRefreshToken(userID string, refreshToken string) error
+	StoreRefreshToken(userID string, familyID string, refreshToken string) error
 	ValidateRefreshToken(userID string, refreshToken string) (bool, error)
 	RevokeRefreshToken(userID string, refreshToken string) error
+	// RotateRefreshToken replaces refreshToken by newRefreshToken, false when refreshToken is no
+	// longer current because it was rotated, revoked or is being rotated by a concurrent request.
+	RotateRefreshToken(userID string, familyID string, refreshToken string, newRefreshToken string) (bool, error)
+	RevokeRefreshTokenFamily(userID string, familyID string) error
+	RevokeAllTokens(userID string) error
+
+	// DetectRefreshTokenReuse is called for a refresh token that is no longer current.
+	// If its family is still alive the token was rotated before and is being replayed,
+	// the family is revoked and a security event is published.
+	DetectRefreshTokenReuse(userID string, familyID string, tokenID string) (bool, error)
+
+	//session methods, a session is a refresh token family
+	RecordSession(session *Session) error
+	ListSessions(userID string) (Sessions, error)
+	RevokeSession(userID string, sessionID string) error
+	// RevokeAllOtherSessions returns the ids of the revoked sessions
+	RevokeAllOtherSessions(userID string, currentSessionID string) ([]string, error)
 }
@@ -1,12 +1,37 @@
 package user
 
+// RefreshTokenStore keeps the refresh tokens that may still be used.
+// Every login starts a family and each rotation hands its place to the next token,
+// so a family has exactly one current token.
 type RefreshTokenStore interface {
-	// Store refresh token with its expiry
-	Set(userID string, refreshToken string) error
+	// Store refresh token with its expiry as the current token of its family
+	Set(userID string, familyID string, refreshToken string) error
 
 	// Check if refresh token exists and is valid (not revoked)
 	Exists(userID string, refreshToken string) (bool, error)
 
-	// Delete refresh token (on logout or rotation)
+	// Delete refresh token (on logout)
 	Delete(userID string, refreshToken string) error
+
+	// Rotate replaces refreshToken by newRefreshToken in one step. It reports false, and
+	// stores nothing, when refreshToken is no longer the current token of its family.
+	Rotate(userID string, familyID string, refreshToken string, newRefreshToken string) (bool, error)
+
+	// Delete every refresh token, family and session of the user
+	RevokeAll(userID string) error
+
+	// Check if the family has not been revoked yet
+	FamilyExists(familyID string) (bool, error)
+
+	// Delete the family together with its current refresh token and session
+	DeleteFamily(userID string, familyID string) error
+
+	// Store the session of a family, it expires together with the family
+	SaveSession(session *Session) error
+
+	// Get a session of the user, nil when it does not exist
+	GetSession(userID string, sessionID string) (*Session, error)
+
+	// List the live sessions of the user
+	ListSessions(userID string) (Sessions, error)
 }
@@ -0,0 +1,145 @@
+package apikeygrpc
+
+import (
+	"context"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/apikey"
+	"github.com/yasinsaee/go-user-service/internal/domain/permission"
+	"github.com/yasinsaee/go-user-service/internal/middleware"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+	apikeypb "github.com/yasinsaee/go-user-service/user-service/apikey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Handler struct {
+	apikeypb.UnimplementedApiKeyServiceServer
+	service apikey.ApiKeyService
+}
+
+func New(service apikey.ApiKeyService) *Handler {
+	return &Handler{service: service}
+}
+
+// -- start helper
+func toApiKeyPB(k *apikey.ApiKey) *apikeypb.ApiKey {
+	pb := &apikeypb.ApiKey{
+		Id:          k.ID.Hex(),
+		UserId:      k.UserID.Hex(),
+		Name:        k.Name,
+		Prefix:      k.Prefix,
+		Permissions: k.Permissions,
+		CreatedAt:   timestamppb.New(k.CreatedAt),
+	}
+	if !k.ExpiresAt.IsZero() {
+		pb.ExpiresAt = timestamppb.New(k.ExpiresAt)
+	}
+	if !k.LastUsedAt.IsZero() {
+		pb.LastUsedAt = timestamppb.New(k.LastUsedAt)
+	}
+	return pb
+}
+
+// owner resolves whose keys the caller acts on, the caller itself unless another
+// user is named, which requires apikey.manage.
+func owner(ctx context.Context, userID string) (primitive.ObjectID, *jwt.JWTClaims, error) {
+	claims, ok := middleware.ClaimsFromContext(ctx)
+	if !ok {
+		return primitive.NilObjectID, nil, status.Errorf(codes.Unauthenticated, "access token is required")
+	}
+
+	if userID == "" || userID == claims.ID {
+		id, err := primitive.ObjectIDFromHex(claims.ID)
+		if err != nil {
+			return primitive.NilObjectID, nil, status.Errorf(codes.Unauthenticated, "invalid user id in token")
+		}
+		return id, claims, nil
+	}
+
+	if !middleware.HasPermissions(claims.Access, permission.ApiKeyManage) {
+		return primitive.NilObjectID, nil, status.Errorf(codes.PermissionDenied, "managing api keys of other users requires %s", permission.ApiKeyManage)
+	}
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return primitive.NilObjectID, nil, status.Errorf(codes.InvalidArgument, "invalid user id format")
+	}
+	return id, claims, nil
+}
+
+//-- end helper
+
+func (h *Handler) CreateApiKey(ctx context.Context, req *apikeypb.CreateApiKeyRequest) (*apikeypb.CreateApiKeyResponse, error) {
+	userID, claims, err := owner(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	// a key must not mint keys wider than itself
+	if claims.Principal == jwt.PrincipalAPIKey {
+		return nil, status.Errorf(codes.PermissionDenied, "api keys cannot create api keys")
+	}
+
+	k := &apikey.ApiKey{
+		UserID:      userID,
+		Name:        req.GetName(),
+		Permissions: req.GetPermissions(),
+	}
+	if req.GetExpiresAt() != nil {
+		k.ExpiresAt = req.GetExpiresAt().AsTime()
+	}
+
+	key, err := h.service.Create(k)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to create api key: %v", err)
+	}
+
+	return &apikeypb.CreateApiKeyResponse{
+		ApiKey: toApiKeyPB(k),
+		Key:    key,
+	}, nil
+}
+
+func (h *Handler) ListApiKeys(ctx context.Context, req *apikeypb.ListApiKeysRequest) (*apikeypb.ListApiKeysResponse, error) {
+	userID, _, err := owner(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := h.service.ListByUser(userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list api keys: %v", err)
+	}
+
+	var pbKeys []*apikeypb.ApiKey
+	for i := range keys {
+		pbKeys = append(pbKeys, toApiKeyPB(&keys[i]))
+	}
+
+	return &apikeypb.ListApiKeysResponse{
+		ApiKeys: pbKeys,
+	}, nil
+}
+
+func (h *Handler) DeleteApiKey(ctx context.Context, req *apikeypb.DeleteApiKeyRequest) (*apikeypb.DeleteApiKeyResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid id format")
+	}
+
+	k, err := h.service.GetByID(id)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "api key not found: %v", err)
+	}
+	if _, _, err := owner(ctx, k.UserID.Hex()); err != nil {
+		return nil, err
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete api key: %v", err)
+	}
+
+	return &apikeypb.DeleteApiKeyResponse{
+		Message: "api key deleted successfully",
+	}, nil
+}
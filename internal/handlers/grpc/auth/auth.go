@@ -0,0 +1,151 @@
+package authgrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/client"
+	"github.com/yasinsaee/go-user-service/internal/domain/token"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+	"github.com/yasinsaee/go-user-service/pkg/util"
+	authpb "github.com/yasinsaee/go-user-service/user-service/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type Handler struct {
+	authpb.UnimplementedAuthServiceServer
+	tService token.TokenService
+	cService client.ClientService
+}
+
+func New(tService token.TokenService, cService client.ClientService) *Handler {
+	return &Handler{tService: tService, cService: cService}
+}
+
+// -- start helper
+
+// authenticateClient checks the Basic credentials a registered client sends in the authorization metadata.
+func (h *Handler) authenticateClient(ctx context.Context) (*client.Client, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md["authorization"]) == 0 {
+		return nil, status.Errorf(codes.Unauthenticated, "client credentials are required")
+	}
+
+	clientID, secret, ok := util.ParseBasicAuth(md["authorization"][0])
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid authorization header")
+	}
+
+	c, err := h.cService.Authenticate(clientID, secret)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "%v", err)
+	}
+	return c, nil
+}
+
+func toJWKPb(k jwt.JSONWebKey) *authpb.JWK {
+	return &authpb.JWK{
+		Kty: k.Kty,
+		Kid: k.Kid,
+		Use: k.Use,
+		Alg: k.Alg,
+		N:   k.N,
+		E:   k.E,
+		Crv: k.Crv,
+		X:   k.X,
+		Y:   k.Y,
+	}
+}
+
+//-- end helper
+
+func (h *Handler) GetJWKS(ctx context.Context, req *authpb.GetJWKSRequest) (*authpb.GetJWKSResponse, error) {
+	set, err := jwt.JWKS()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load verification keys: %v", err)
+	}
+
+	var keys []*authpb.JWK
+	for _, k := range set.Keys {
+		keys = append(keys, toJWKPb(k))
+	}
+
+	return &authpb.GetJWKSResponse{
+		Keys: keys,
+	}, nil
+}
+
+func (h *Handler) RevokeToken(ctx context.Context, req *authpb.RevokeTokenRequest) (*authpb.RevokeTokenResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "token is required")
+	}
+
+	if err := h.tService.RevokeToken(req.GetToken()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to revoke token: %v", err)
+	}
+
+	return &authpb.RevokeTokenResponse{
+		Success: true,
+		Message: "token revoked",
+	}, nil
+}
+
+func (h *Handler) RevokeUserTokens(ctx context.Context, req *authpb.RevokeUserTokensRequest) (*authpb.RevokeUserTokensResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "user id is required")
+	}
+
+	before := time.Now().UTC()
+	if req.GetBefore() != nil {
+		before = req.GetBefore().AsTime()
+	}
+
+	if err := h.tService.RevokeUserTokens(req.GetUserId(), before); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke user tokens: %v", err)
+	}
+
+	return &authpb.RevokeUserTokensResponse{
+		Success: true,
+		Message: "user tokens revoked",
+	}, nil
+}
+
+func (h *Handler) IntrospectToken(ctx context.Context, req *authpb.IntrospectTokenRequest) (*authpb.IntrospectTokenResponse, error) {
+	if _, err := h.authenticateClient(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.GetToken() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "token is required")
+	}
+
+	result, err := h.tService.Introspect(req.GetToken(), token.TypeFromHint(req.GetTokenTypeHint()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to introspect token: %v", err)
+	}
+
+	if !result.Active {
+		return &authpb.IntrospectTokenResponse{Active: false}, nil
+	}
+
+	return &authpb.IntrospectTokenResponse{
+		Active:    true,
+		TokenType: string(result.TokenType),
+		Sub:       result.Subject,
+		Username:  result.Username,
+		Roles:     result.Roles,
+		Access:    result.Access,
+		Jti:       result.TokenID,
+		Iss:       result.Issuer,
+		Aud:       result.Audience,
+		Iat:       result.IssuedAt.Unix(),
+		Exp:       result.ExpiresAt.Unix(),
+		Principal: string(result.Principal),
+		ClientId:  result.ClientID,
+		Amr:       result.Authentication.AMR,
+		Acr:       result.Authentication.ACR,
+		AuthTime:  result.Authentication.AuthTime,
+	}, nil
+}
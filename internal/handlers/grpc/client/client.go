@@ -0,0 +1,164 @@
+package clientgrpc
+
+import (
+	"context"
+
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/client"
+	"github.com/yasinsaee/go-user-service/internal/domain/token"
+	clientpb "github.com/yasinsaee/go-user-service/user-service/client"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Handler struct {
+	clientpb.UnimplementedClientServiceServer
+	service  client.ClientService
+	tService token.TokenService
+}
+
+func New(service client.ClientService, tService token.TokenService) *Handler {
+	return &Handler{service: service, tService: tService}
+}
+
+// -- start helper
+func toClientPB(c *client.Client) *clientpb.Client {
+	return &clientpb.Client{
+		Id:           c.ID.Hex(),
+		ClientId:     c.ClientID,
+		Name:         c.Name,
+		Description:  c.Description,
+		IsActive:     c.IsActive,
+		CreatedAt:    timestamppb.New(c.CreatedAt),
+		Scopes:       c.Scopes,
+		RedirectUris: c.RedirectURIs,
+		Public:       c.Public,
+	}
+}
+
+//-- end helper
+
+func (h *Handler) CreateClient(ctx context.Context, req *clientpb.CreateClientRequest) (*clientpb.CreateClientResponse, error) {
+	c := &client.Client{
+		Name:         req.GetName(),
+		Description:  req.GetDescription(),
+		Scopes:       req.GetScopes(),
+		RedirectURIs: req.GetRedirectUris(),
+		Public:       req.GetPublic(),
+	}
+
+	secret, err := h.service.Register(c)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create client: %v", err)
+	}
+
+	return &clientpb.CreateClientResponse{
+		Client:       toClientPB(c),
+		ClientSecret: secret,
+	}, nil
+}
+
+func (h *Handler) GetClient(ctx context.Context, req *clientpb.GetClientRequest) (*clientpb.GetClientResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid id format")
+	}
+
+	c, err := h.service.GetByID(id)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "client not found: %v", err)
+	}
+
+	return &clientpb.GetClientResponse{
+		Client: toClientPB(c),
+	}, nil
+}
+
+func (h *Handler) ListClients(ctx context.Context, req *clientpb.ListClientRequest) (*clientpb.ListClientResponse, error) {
+	clients, err := h.service.ListAll()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list clients: %v", err)
+	}
+
+	var pbClients []*clientpb.Client
+	for i := range clients {
+		pbClients = append(pbClients, toClientPB(&clients[i]))
+	}
+
+	return &clientpb.ListClientResponse{
+		Clients: pbClients,
+	}, nil
+}
+
+func (h *Handler) UpdateClient(ctx context.Context, req *clientpb.UpdateClientRequest) (*clientpb.UpdateClientResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid id format")
+	}
+
+	c, err := h.service.GetByID(id)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "client not found: %v", err)
+	}
+
+	if name := req.GetName(); name != "" {
+		c.Name = name
+	}
+	if desc := req.GetDescription(); desc != "" {
+		c.Description = desc
+	}
+	if len(req.GetScopes()) > 0 {
+		c.Scopes = req.GetScopes()
+	}
+	if len(req.GetRedirectUris()) > 0 {
+		c.RedirectURIs = req.GetRedirectUris()
+	}
+
+	deactivated := false
+	if req.IsActive != nil {
+		deactivated = c.IsActive && !req.GetIsActive()
+		c.IsActive = req.GetIsActive()
+	}
+
+	if err := h.service.Update(c); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update client: %v", err)
+	}
+
+	// tokens already issued to a disabled client must stop working right away
+	if deactivated {
+		if err := h.tService.RevokeUserTokens(c.ClientID, time.Now().UTC()); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to revoke client tokens: %v", err)
+		}
+	}
+
+	return &clientpb.UpdateClientResponse{
+		Client: toClientPB(c),
+	}, nil
+}
+
+func (h *Handler) DeleteClient(ctx context.Context, req *clientpb.DeleteClientRequest) (*clientpb.DeleteClientResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid id format")
+	}
+
+	c, err := h.service.GetByID(id)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "client not found: %v", err)
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete client: %v", err)
+	}
+
+	if err := h.tService.RevokeUserTokens(c.ClientID, time.Now().UTC()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke client tokens: %v", err)
+	}
+
+	return &clientpb.DeleteClientResponse{
+		Message: "client deleted successfully",
+	}, nil
+}
@@ -0,0 +1,33 @@
+package usergrpc
+
+import (
+	"bytes"
+	"context"
+
+	userpb "github.com/yasinsaee/go-user-service/user-service/user"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ImportUsers creates users exported from another application. They keep their password
+// hashes until their first login, which replaces them by native ones.
+func (h *Handler) ImportUsers(ctx context.Context, req *userpb.ImportUsersRequest) (*userpb.ImportUsersResponse, error) {
+	if len(req.GetData()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "data is required")
+	}
+
+	result, err := h.iService.Import(bytes.NewReader(req.GetData()), req.GetFormat(), req.GetDryRun())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to read users: %v", err)
+	}
+
+	resp := &userpb.ImportUsersResponse{Imported: int32(result.Imported)}
+	for _, f := range result.Failures {
+		resp.Failures = append(resp.Failures, &userpb.ImportFailure{
+			Line:     int32(f.Line),
+			Username: f.Username,
+			Reason:   f.Reason,
+		})
+	}
+	return resp, nil
+}
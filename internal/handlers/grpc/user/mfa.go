@@ -0,0 +1,217 @@
+package usergrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/mfa"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+	userpb "github.com/yasinsaee/go-user-service/user-service/user"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// -- #start helpers
+
+// ownAccount returns the user of the access token. Only the user's own logins may change
+// how the user signs in, not api keys or tokens delegated to an oauth client.
+func (h *Handler) ownAccount(ctx context.Context) (*user.User, error) {
+	claims, err := h.currentClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Principal == jwt.PrincipalAPIKey || claims.ClientID != "" {
+		return nil, status.Errorf(codes.PermissionDenied, "sign in methods can only be managed by the user")
+	}
+
+	u, err := h.service.GetByID(claims.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "user not found")
+	}
+	return u, nil
+}
+
+func mfaError(err error) error {
+	switch {
+	case errors.Is(err, mfa.ErrInvalidCode):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, mfa.ErrTooManyAttempts):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, mfa.ErrNotEnrolled), errors.Is(err, mfa.ErrAlreadyEnabled), errors.Is(err, mfa.ErrNoPendingEnrollment),
+		errors.Is(err, mfa.ErrTrustDisabled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, mfa.ErrDeviceNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "two-factor authentication failed: %v", err)
+	}
+}
+
+// trustedDevice describes the device a request comes from, like its session.
+func (h *Handler) trustedDevice(ctx context.Context, userID string) *mfa.TrustedDevice {
+	session := h.clientSession(ctx, userID, "")
+	return &mfa.TrustedDevice{
+		UserID:     userID,
+		DeviceName: session.DeviceName,
+		UserAgent:  session.UserAgent,
+		IP:         session.IP,
+	}
+}
+
+func toTrustedDevicePb(d mfa.TrustedDevice) *userpb.TrustedDevice {
+	return &userpb.TrustedDevice{
+		Id:         d.ID,
+		DeviceName: d.DeviceName,
+		UserAgent:  d.UserAgent,
+		Ip:         d.IP,
+		CreatedAt:  timestamppb.New(d.CreatedAt),
+		ExpiresAt:  timestamppb.New(d.ExpiresAt),
+	}
+}
+
+//-- end helpers
+
+func (h *Handler) VerifyMFA(ctx context.Context, req *userpb.VerifyMFARequest) (*userpb.LoginResponse, error) {
+	claims, err := jwt.ValidateMFAToken(req.GetMfaToken())
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired mfa token")
+	}
+
+	u, err := h.service.GetByID(claims.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "user not found")
+	}
+
+	if err := h.mService.Verify(u, req.GetCode()); err != nil {
+		return nil, mfaError(err)
+	}
+
+	// the challenge is answered, it must not be answered again with another code
+	if err := h.tService.RevokeToken(req.GetMfaToken()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke mfa token: %v", err)
+	}
+
+	// recovery codes stand in for the authenticator app, both count as totp
+	resp, err := h.loginResponse(ctx, u, claims.Username, claims.Authentication.Add(jwt.AMRTOTP))
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetTrustDevice() {
+		deviceToken, err := h.mService.TrustDevice(u, h.trustedDevice(ctx, u.ID.Hex()))
+		// without trusted devices the login still succeeds, just without a device token
+		if err != nil && !errors.Is(err, mfa.ErrTrustDisabled) {
+			return nil, mfaError(err)
+		}
+		resp.DeviceToken = deviceToken
+	}
+	return resp, nil
+}
+
+func (h *Handler) EnrollTOTP(ctx context.Context, req *userpb.EnrollTOTPRequest) (*userpb.EnrollTOTPResponse, error) {
+	u, err := h.ownAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, uri, err := h.mService.EnrollTOTP(u)
+	if err != nil {
+		return nil, mfaError(err)
+	}
+
+	return &userpb.EnrollTOTPResponse{
+		Secret:     secret,
+		OtpauthUri: uri,
+	}, nil
+}
+
+func (h *Handler) ConfirmTOTP(ctx context.Context, req *userpb.ConfirmTOTPRequest) (*userpb.RecoveryCodesResponse, error) {
+	u, err := h.ownAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := h.mService.ConfirmTOTP(u, req.GetCode())
+	if err != nil {
+		return nil, mfaError(err)
+	}
+
+	return &userpb.RecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (h *Handler) DisableMFA(ctx context.Context, req *userpb.DisableMFARequest) (*userpb.DisableMFAResponse, error) {
+	u, err := h.ownAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.mService.Disable(u, req.GetCode()); err != nil {
+		return nil, mfaError(err)
+	}
+
+	return &userpb.DisableMFAResponse{
+		Success: true,
+		Message: "two-factor authentication disabled",
+	}, nil
+}
+
+func (h *Handler) RegenerateRecoveryCodes(ctx context.Context, req *userpb.RegenerateRecoveryCodesRequest) (*userpb.RecoveryCodesResponse, error) {
+	u, err := h.ownAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := h.mService.RegenerateRecoveryCodes(u, req.GetCode())
+	if err != nil {
+		return nil, mfaError(err)
+	}
+
+	return &userpb.RecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (h *Handler) ListTrustedDevices(ctx context.Context, req *userpb.ListTrustedDevicesRequest) (*userpb.ListTrustedDevicesResponse, error) {
+	u, err := h.ownAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	devices, err := h.mService.ListTrustedDevices(u)
+	if err != nil {
+		return nil, mfaError(err)
+	}
+
+	var pbDevices []*userpb.TrustedDevice
+	for _, d := range devices {
+		pbDevices = append(pbDevices, toTrustedDevicePb(d))
+	}
+
+	return &userpb.ListTrustedDevicesResponse{
+		Devices: pbDevices,
+	}, nil
+}
+
+func (h *Handler) RevokeTrustedDevice(ctx context.Context, req *userpb.RevokeTrustedDeviceRequest) (*userpb.RevokeTrustedDeviceResponse, error) {
+	u, err := h.ownAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.mService.RevokeTrustedDevice(u, req.GetId()); err != nil {
+		return nil, mfaError(err)
+	}
+
+	message := "trusted device revoked"
+	if req.GetId() == "" {
+		message = "every trusted device revoked"
+	}
+	return &userpb.RevokeTrustedDeviceResponse{
+		Success: true,
+		Message: message,
+	}, nil
+}
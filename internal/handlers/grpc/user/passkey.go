@@ -0,0 +1,134 @@
+package usergrpc
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/passkey"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+	userpb "github.com/yasinsaee/go-user-service/user-service/user"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// -- #start helpers
+
+func toPasskeyPb(p *user.Passkey) *userpb.Passkey {
+	pb := &userpb.Passkey{
+		Id:           base64.RawURLEncoding.EncodeToString(p.ID),
+		Nickname:     p.Nickname,
+		Transports:   p.Transports,
+		CreatedAt:    timestamppb.New(p.CreatedAt),
+		CloneWarning: p.CloneWarning,
+	}
+	if !p.LastUsedAt.IsZero() {
+		pb.LastUsedAt = timestamppb.New(p.LastUsedAt)
+	}
+	return pb
+}
+
+func passkeyError(err error) error {
+	switch {
+	case errors.Is(err, passkey.ErrInvalidPasskey), errors.Is(err, passkey.ErrCloneDetected):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, passkey.ErrInvalidCeremony):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, passkey.ErrPasskeyNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "passkey ceremony failed: %v", err)
+	}
+}
+
+//-- end helpers
+
+func (h *Handler) BeginPasskeyRegistration(ctx context.Context, req *userpb.BeginPasskeyRegistrationRequest) (*userpb.BeginPasskeyResponse, error) {
+	u, err := h.ownAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	options, ceremonyID, err := h.kService.BeginRegistration(u)
+	if err != nil {
+		return nil, passkeyError(err)
+	}
+
+	return &userpb.BeginPasskeyResponse{
+		CeremonyId:  ceremonyID,
+		OptionsJson: string(options),
+	}, nil
+}
+
+func (h *Handler) FinishPasskeyRegistration(ctx context.Context, req *userpb.FinishPasskeyRegistrationRequest) (*userpb.PasskeyResponse, error) {
+	u, err := h.ownAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := h.kService.FinishRegistration(u, req.GetCeremonyId(), req.GetNickname(), []byte(req.GetCredentialJson()))
+	if err != nil {
+		return nil, passkeyError(err)
+	}
+
+	return &userpb.PasskeyResponse{
+		Passkey: toPasskeyPb(p),
+	}, nil
+}
+
+func (h *Handler) ListPasskeys(ctx context.Context, req *userpb.ListPasskeysRequest) (*userpb.ListPasskeysResponse, error) {
+	u, err := h.ownAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pbPasskeys []*userpb.Passkey
+	for i := range u.Passkeys {
+		pbPasskeys = append(pbPasskeys, toPasskeyPb(&u.Passkeys[i]))
+	}
+
+	return &userpb.ListPasskeysResponse{
+		Passkeys: pbPasskeys,
+	}, nil
+}
+
+func (h *Handler) DeletePasskey(ctx context.Context, req *userpb.DeletePasskeyRequest) (*userpb.DeletePasskeyResponse, error) {
+	u, err := h.ownAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.kService.DeletePasskey(u, req.GetId()); err != nil {
+		return nil, passkeyError(err)
+	}
+
+	return &userpb.DeletePasskeyResponse{
+		Success: true,
+		Message: "passkey deleted",
+	}, nil
+}
+
+func (h *Handler) BeginPasskeyLogin(ctx context.Context, req *userpb.BeginPasskeyLoginRequest) (*userpb.BeginPasskeyResponse, error) {
+	options, ceremonyID, err := h.kService.BeginLogin()
+	if err != nil {
+		return nil, passkeyError(err)
+	}
+
+	return &userpb.BeginPasskeyResponse{
+		CeremonyId:  ceremonyID,
+		OptionsJson: string(options),
+	}, nil
+}
+
+// FinishPasskeyLogin issues tokens without asking for the second factor,
+// a passkey with user verification is already two factors.
+func (h *Handler) FinishPasskeyLogin(ctx context.Context, req *userpb.FinishPasskeyLoginRequest) (*userpb.LoginResponse, error) {
+	u, err := h.kService.FinishLogin(req.GetCeremonyId(), []byte(req.GetCredentialJson()))
+	if err != nil {
+		return nil, passkeyError(err)
+	}
+
+	return h.loginResponse(ctx, u, u.Username, jwt.NewAuthentication(jwt.AMRWebAuthn))
+}
@@ -0,0 +1,117 @@
+package usergrpc
+
+import (
+	"context"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+	userpb "github.com/yasinsaee/go-user-service/user-service/user"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// -- #start helpers
+
+// stepUpAuthentication verifies the user again with a passkey, or with the password and
+// the second factor when the user has one, and returns the new authentication.
+func (h *Handler) stepUpAuthentication(u *user.User, req *userpb.StepUpRequest) (jwt.Authentication, error) {
+	if req.GetCeremonyId() != "" {
+		pu, err := h.kService.FinishLogin(req.GetCeremonyId(), []byte(req.GetCredentialJson()))
+		if err != nil {
+			return jwt.Authentication{}, passkeyError(err)
+		}
+		if pu.ID != u.ID {
+			return jwt.Authentication{}, status.Errorf(codes.Unauthenticated, "passkey belongs to another account")
+		}
+		return jwt.NewAuthentication(jwt.AMRWebAuthn), nil
+	}
+
+	if req.GetPassword() == "" {
+		return jwt.Authentication{}, status.Errorf(codes.InvalidArgument, "password or passkey is required")
+	}
+	if err := h.service.VerifyPassword(u, req.GetPassword()); err != nil {
+		return jwt.Authentication{}, status.Errorf(codes.Unauthenticated, "invalid password")
+	}
+	auth := jwt.NewAuthentication(jwt.AMRPassword)
+
+	// a step-up must not be weaker than the login
+	if u.MFA.Enabled {
+		if req.GetCode() == "" {
+			return jwt.Authentication{}, status.Errorf(codes.InvalidArgument, "two-factor code is required")
+		}
+		if err := h.mService.Verify(u, req.GetCode()); err != nil {
+			return jwt.Authentication{}, mfaError(err)
+		}
+		auth = auth.Add(jwt.AMRTOTP)
+	}
+	return auth, nil
+}
+
+//-- end helpers
+
+// StepUp continues the caller's session with tokens carrying a fresh authentication,
+// for methods whose policy requires a recent or a specific one.
+func (h *Handler) StepUp(ctx context.Context, req *userpb.StepUpRequest) (*userpb.RefreshTokenResponse, error) {
+	u, err := h.ownAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := h.currentClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the session's refresh token is rotated away, a second one would leave the old token
+	// behind and the next refresh with it would be taken for a stolen token
+	userID := u.ID.Hex()
+	previous := req.GetRefreshToken()
+	if previous == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "refresh token is required")
+	}
+	rc, err := jwt.ValidateRefreshToken(previous)
+	if err != nil || rc.ID != userID || rc.FamilyID != claims.SessionID {
+		return nil, status.Errorf(codes.InvalidArgument, "refresh token does not belong to this session")
+	}
+
+	auth, err := h.stepUpAuthentication(u, req)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, permissions := h.toUserJwtMeta(u)
+	tc := jwt.TokenConfig{
+		ID:             userID,
+		Username:       claims.Username,
+		Roles:          roles,
+		Access:         permissions,
+		FamilyID:       claims.SessionID,
+		Authentication: auth,
+	}
+
+	accessToken, accessExpTime, err := tc.GenerateAccessToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate token: %v", err)
+	}
+	refreshToken, _, err := tc.GenerateRefreshToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate refresh token: %v", err)
+	}
+
+	rotated, err := h.service.RotateRefreshToken(userID, tc.FamilyID, previous, refreshToken)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store refresh token: %v", err)
+	}
+	if !rotated {
+		return nil, status.Errorf(codes.InvalidArgument, "refresh token is no longer current")
+	}
+	if err := h.service.RecordSession(h.clientSession(ctx, userID, tc.FamilyID)); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store session: %v", err)
+	}
+
+	return &userpb.RefreshTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    timestamppb.New(accessExpTime),
+	}, nil
+}
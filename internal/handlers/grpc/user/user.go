@@ -2,37 +2,62 @@ package usergrpc
 
 import (
 	"context"
+	"errors"
+	"net"
+	"strings"
 	"sync"
 
 	"github.com/yasinsaee/go-user-service/internal/app/config"
+	"github.com/yasinsaee/go-user-service/internal/domain/mfa"
+	"github.com/yasinsaee/go-user-service/internal/domain/otp"
+	"github.com/yasinsaee/go-user-service/internal/domain/passkey"
 	"github.com/yasinsaee/go-user-service/internal/domain/permission"
 	"github.com/yasinsaee/go-user-service/internal/domain/role"
+	"github.com/yasinsaee/go-user-service/internal/domain/token"
 	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/internal/middleware"
 	"github.com/yasinsaee/go-user-service/pkg/jwt"
 	userpb "github.com/yasinsaee/go-user-service/user-service/user"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Handler struct {
 	userpb.UnimplementedUserServiceServer
-	service    user.UserService
-	rService   role.RoleService
-	pService   permission.PermissionService
-	roleCache  map[string]*role.Role
-	permCache  map[string]*permission.Permission
-	cacheMutex sync.RWMutex
+	service      user.UserService
+	rService     role.RoleService
+	pService     permission.PermissionService
+	tService     token.TokenService
+	oService     otp.OTPService
+	mService     mfa.MFAService
+	kService     passkey.PasskeyService
+	iService     user.UserImporter
+	autoRegister bool         // LoginWithOTP registers unknown phone numbers
+	proxies      []*net.IPNet // peers whose x-forwarded-for is believed
+	roleCache    map[string]*role.Role
+	permCache    map[string]*permission.Permission
+	cacheMutex   sync.RWMutex
 }
 
-func New(service user.UserService, rService role.RoleService, pService permission.PermissionService) *Handler {
+func New(service user.UserService, rService role.RoleService, pService permission.PermissionService, tService token.TokenService, oService otp.OTPService, mService mfa.MFAService, kService passkey.PasskeyService, iService user.UserImporter, autoRegister bool, proxies []*net.IPNet) *Handler {
 	return &Handler{
-		service:   service,
-		rService:  rService,
-		pService:  pService,
-		roleCache: make(map[string]*role.Role),
-		permCache: make(map[string]*permission.Permission),
+		service:      service,
+		rService:     rService,
+		pService:     pService,
+		tService:     tService,
+		oService:     oService,
+		mService:     mService,
+		kService:     kService,
+		iService:     iService,
+		autoRegister: autoRegister,
+		proxies:      proxies,
+		roleCache:    make(map[string]*role.Role),
+		permCache:    make(map[string]*permission.Permission),
 	}
 }
 
@@ -142,7 +167,7 @@ func (h *Handler) toUserPb(u *user.User) *userpb.User {
 		Username:     u.Username,
 		Email:        u.Email,
 		PhoneNumber:  u.PhoneNumber,
-		IsActive:     u.IsActive,
+		IsActive:     u.Active(),
 		CreatedAt:    timestamppb.New(u.CreatedAt),
 		UpdatedAt:    timestamppb.New(u.UpdatedAt),
 		LastLogin:    timestamppb.New(u.LastLogin),
@@ -160,21 +185,128 @@ func (h *Handler) toUserJwtMeta(u *user.User) (roles []string, permissions []str
 	return
 }
 
-//-- end helpers
+// currentClaims returns the caller's access token claims put in the context by the interceptor.
+func (h *Handler) currentClaims(ctx context.Context) (*jwt.JWTClaims, error) {
+	claims, ok := middleware.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "access token is required")
+	}
+	return claims, nil
+}
 
-func (h *Handler) Login(ctx context.Context, req *userpb.LoginRequest) (*userpb.LoginResponse, error) {
-	u, err := h.service.Login(req.GetUsername(), req.GetPassword())
+// clientSession describes the device a request comes from.
+// The device name is sent by the client in the x-device-name metadata.
+func (h *Handler) clientSession(ctx context.Context, userID, sessionID string) *user.Session {
+	session := &user.Session{
+		ID:     sessionID,
+		UserID: userID,
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get("x-device-name"); len(v) > 0 {
+		session.DeviceName = v[0]
+	}
+	if v := md.Get("user-agent"); len(v) > 0 {
+		session.UserAgent = v[0]
+	}
+
+	session.IP = h.clientIP(ctx, md)
+	return session
+}
+
+// clientIP returns the address of the client. Behind a proxy the peer is the proxy itself,
+// so x-forwarded-for is followed from the right while the hops are trusted proxies; anyone
+// else could write any address into it.
+func (h *Handler) clientIP(ctx context.Context, md metadata.MD) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	ip := p.Addr.String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	hops := strings.Split(strings.Join(md.Get("x-forwarded-for"), ","), ",")
+	for i := len(hops) - 1; i >= 0 && h.trustedProxy(ip); i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+	}
+	return ip
+}
+
+func (h *Handler) trustedProxy(ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, n := range h.proxies {
+		if n.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func toSessionPb(s user.Session, currentSessionID string) *userpb.Session {
+	return &userpb.Session{
+		Id:          s.ID,
+		DeviceName:  s.DeviceName,
+		UserAgent:   s.UserAgent,
+		Ip:          s.IP,
+		CreatedAt:   timestamppb.New(s.CreatedAt),
+		LastRefresh: timestamppb.New(s.LastRefresh),
+		Current:     s.ID == currentSessionID,
+	}
+}
+
+// loginOrChallenge answers a login with the tokens, or with a challenge for the second factor when the user
+// has one. A trusted device skips the challenge, the second factor is then not part of the authentication.
+func (h *Handler) loginOrChallenge(ctx context.Context, u *user.User, username string, auth jwt.Authentication, deviceToken string) (*userpb.LoginResponse, error) {
+	if !u.MFA.Enabled || h.mService.IsTrustedDevice(u, deviceToken) {
+		return h.loginResponse(ctx, u, username, auth)
+	}
+
+	mfaToken, _, err := jwt.GenerateMFAToken(u.ID.Hex(), username, auth)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to login user: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to generate mfa token: %v", err)
+	}
+	return &userpb.LoginResponse{
+		MfaRequired: true,
+		MfaToken:    mfaToken,
+	}, nil
+}
+
+// loginResponse issues the access and refresh token pair of a new session, auth tells how the user signed in.
+// A user whose password has to be changed first only gets a token for UpdatePassword, whatever the login
+// method, otherwise an OTP or passkey login would skip the change.
+func (h *Handler) loginResponse(ctx context.Context, u *user.User, username string, auth jwt.Authentication) (*userpb.LoginResponse, error) {
+	if !u.Active() {
+		return nil, status.Error(codes.PermissionDenied, user.ErrDeactivated.Error())
+	}
+	if reason := h.service.PasswordChangeReason(u); reason != "" {
+		changeToken, _, err := jwt.GeneratePasswordChangeToken(u.ID.Hex(), username, reason, auth)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to generate password change token: %v", err)
+		}
+		return &userpb.LoginResponse{
+			PasswordChangeRequired: true,
+			PasswordChangeToken:    changeToken,
+			PasswordChangeReason:   reason,
+		}, nil
 	}
 
 	roles, permissions := h.toUserJwtMeta(u)
 
 	tokenConfig := jwt.TokenConfig{
-		ID:       u.ID.Hex(),
-		Username: req.GetUsername(),
-		Roles:    roles,
-		Access:   permissions,
+		ID:             u.ID.Hex(),
+		Username:       username,
+		Roles:          roles,
+		Access:         permissions,
+		Authentication: auth,
 	}
 	accessToken, _, err := tokenConfig.GenerateAccessToken()
 	if err != nil {
@@ -186,10 +318,14 @@ func (h *Handler) Login(ctx context.Context, req *userpb.LoginRequest) (*userpb.
 		return nil, status.Errorf(codes.Internal, "failed to generate refresh token: %v", err)
 	}
 
-	if err := h.service.StoreRefreshToken(u.ID.Hex(), refreshToken); err != nil {
+	if err := h.service.StoreRefreshToken(u.ID.Hex(), tokenConfig.FamilyID, refreshToken); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to store refresh token: %v", err)
 	}
 
+	if err := h.service.RecordSession(h.clientSession(ctx, u.ID.Hex(), tokenConfig.FamilyID)); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store session: %v", err)
+	}
+
 	return &userpb.LoginResponse{
 		User:         h.toUserPb(u),
 		AccessToken:  accessToken,
@@ -197,6 +333,98 @@ func (h *Handler) Login(ctx context.Context, req *userpb.LoginRequest) (*userpb.
 	}, nil
 }
 
+// passwordOwner returns the user whose password UpdatePassword sets: the one of the password
+// change token a login answered with, or any user for callers with the user.update_password permission.
+func (h *Handler) passwordOwner(ctx context.Context, req *userpb.UpdatePasswordUser) (*user.User, error) {
+	if changeToken := req.GetPasswordChangeToken(); changeToken != "" {
+		claims, err := jwt.ValidatePasswordChangeToken(changeToken)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid or expired password change token")
+		}
+		u, err := h.service.GetByID(claims.ID)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		return u, nil
+	}
+
+	claims, ok := middleware.ClaimsFromContext(ctx)
+	if !ok || !middleware.HasPermissions(claims.Access, permission.UserUpdatePassword) {
+		return nil, status.Errorf(codes.PermissionDenied, "not allowed to update the password")
+	}
+	u, err := h.service.GetByUsername(req.GetUsername())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "failed to found user: %v", err)
+	}
+	return u, nil
+}
+
+// passwordPolicyError turns a password rejected by the policy into InvalidArgument with a
+// BadRequest field violation per failed rule, it returns nil for any other error.
+func passwordPolicyError(err error, field string) error {
+	var policyErr *user.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return nil
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range policyErr.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: v.Description,
+			Reason:      v.Rule,
+		})
+	}
+
+	st, detailsErr := status.New(codes.InvalidArgument, policyErr.Error()).WithDetails(badRequest)
+	if detailsErr != nil {
+		return status.Error(codes.InvalidArgument, policyErr.Error())
+	}
+	return st.Err()
+}
+
+//-- end helpers
+
+func (h *Handler) Login(ctx context.Context, req *userpb.LoginRequest) (*userpb.LoginResponse, error) {
+	u, err := h.service.Login(req.GetUsername(), req.GetPassword())
+	if errors.Is(err, user.ErrDeactivated) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to login user: %v", err)
+	}
+
+	return h.loginOrChallenge(ctx, u, req.GetUsername(), jwt.NewAuthentication(jwt.AMRPassword), req.GetDeviceToken())
+}
+
+func (h *Handler) LoginWithOTP(ctx context.Context, req *userpb.LoginWithOTPRequest) (*userpb.LoginResponse, error) {
+	if req.GetPhoneNumber() == "" || req.GetCode() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "phone number and code are required")
+	}
+
+	ok, err := h.oService.ValidateCode(req.GetPhoneNumber(), otp.OtpTypeLogin, req.GetCode())
+	if err != nil || !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid otp")
+	}
+
+	var newUser *user.User
+	if h.autoRegister {
+		newUser = &user.User{
+			FirstName: req.GetFirstName(),
+			LastName:  req.GetLastName(),
+		}
+	}
+	u, err := h.service.LoginWithPhone(req.GetPhoneNumber(), newUser)
+	if errors.Is(err, user.ErrDeactivated) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "failed to login user: %v", err)
+	}
+
+	return h.loginOrChallenge(ctx, u, u.Username, jwt.NewAuthentication(jwt.AMROTP), req.GetDeviceToken())
+}
+
 func (h *Handler) Register(ctx context.Context, req *userpb.RegisterUser) (*userpb.UserResponse, error) {
 	u := &user.User{
 		FirstName:    req.GetFirstName(),
@@ -208,6 +436,12 @@ func (h *Handler) Register(ctx context.Context, req *userpb.RegisterUser) (*user
 		Password:     req.GetPassword(),
 	}
 	if req.GetRoles() != nil {
+		// registration is public, picking roles is not
+		claims, ok := middleware.ClaimsFromContext(ctx)
+		if !ok || !middleware.HasPermissions(claims.Access, permission.UserAssignRoles) {
+			return nil, status.Errorf(codes.PermissionDenied, "not allowed to assign roles")
+		}
+
 		for _, r := range req.GetRoles() {
 			roleID, err := primitive.ObjectIDFromHex(r)
 			if err != nil {
@@ -232,6 +466,9 @@ func (h *Handler) Register(ctx context.Context, req *userpb.RegisterUser) (*user
 
 	}
 	if err := h.service.Register(username, u); err != nil {
+		if perr := passwordPolicyError(err, "password"); perr != nil {
+			return nil, perr
+		}
 		return nil, status.Errorf(codes.Internal, "failed to register user: %v", err)
 	}
 
@@ -247,6 +484,9 @@ func (h *Handler) ResetPassword(ctx context.Context, req *userpb.ResetPasswordUs
 	}
 
 	if err := h.service.ResetPassword(u, req.GetCurrentPassword(), req.GetNewPassword(), req.GetRepeatNewPassword()); err != nil {
+		if perr := passwordPolicyError(err, "new_password"); perr != nil {
+			return nil, perr
+		}
 		return nil, status.Errorf(codes.Internal, "failed to reset password user: %v", err)
 	}
 
@@ -321,15 +561,56 @@ func (h *Handler) Update(ctx context.Context, req *userpb.UpdateUser) (*userpb.U
 }
 
 func (h *Handler) UpdatePassword(ctx context.Context, req *userpb.UpdatePasswordUser) (*userpb.UserResponse, error) {
-	u, err := h.service.GetByUsername(req.GetUsername())
+	u, err := h.passwordOwner(ctx, req)
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "failed to found user: %v", err)
+		return nil, err
 	}
 
 	if err := h.service.UpdatePassword(u, req.GetNewPassword(), req.GetRepeatNewPassword()); err != nil {
+		if perr := passwordPolicyError(err, "new_password"); perr != nil {
+			return nil, perr
+		}
 		return nil, status.Errorf(codes.Internal, "failed to reset password user: %v", err)
 	}
 
+	// a password change token sets the password once, a rejected password may be tried again
+	if changeToken := req.GetPasswordChangeToken(); changeToken != "" {
+		if err := h.tService.RevokeToken(changeToken); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to revoke password change token: %v", err)
+		}
+	}
+
+	return &userpb.UserResponse{
+		User: h.toUserPb(u),
+	}, nil
+}
+
+// RequirePasswordChange makes the user change the password at the next login.
+func (h *Handler) RequirePasswordChange(ctx context.Context, req *userpb.RequirePasswordChangeRequest) (*userpb.UserResponse, error) {
+	u, err := h.service.GetByUsername(req.GetUsername())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "failed to found user: %v", err)
+	}
+
+	if err := h.service.RequirePasswordChange(u); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to require a password change: %v", err)
+	}
+
+	return &userpb.UserResponse{
+		User: h.toUserPb(u),
+	}, nil
+}
+
+func (h *Handler) SetUserActive(ctx context.Context, req *userpb.SetUserActiveRequest) (*userpb.UserResponse, error) {
+	u, err := h.service.GetByID(req.GetUserId())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "failed to found user: %v", err)
+	}
+
+	if err := h.service.SetActive(u, req.GetActive()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update user: %v", err)
+	}
+
 	return &userpb.UserResponse{
 		User: h.toUserPb(u),
 	}, nil
@@ -345,27 +626,29 @@ func (h *Handler) RefreshToken(ctx context.Context, req *userpb.RefreshTokenRequ
 	if err != nil {
 		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired refresh token")
 	}
+	// it would come back with the user's full permissions instead of the client's scopes
+	if claims.ClientID != "" {
+		return nil, status.Errorf(codes.PermissionDenied, "refresh token belongs to an oauth client, refresh it at the token endpoint")
+	}
 
 	userID := claims.ID
 
-	exists, err := h.service.ValidateRefreshToken(userID, refreshToken)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "internal server error")
-	}
-	if !exists {
-		return nil, status.Errorf(codes.Unauthenticated, "refresh token has been revoked")
-	}
-
 	u, err := h.service.GetByID(userID)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "user not found")
 	}
+	if !u.Active() {
+		return nil, status.Error(codes.PermissionDenied, user.ErrDeactivated.Error())
+	}
 	roles, permissions := h.toUserJwtMeta(u)
+	// a refresh keeps the session's authentication, only a step-up renews it
 	tc := jwt.TokenConfig{
-		ID:       u.ID.Hex(),
-		Username: u.Username,
-		Roles:    roles,
-		Access:   permissions,
+		ID:             u.ID.Hex(),
+		Username:       u.Username,
+		Roles:          roles,
+		Access:         permissions,
+		FamilyID:       claims.FamilyID,
+		Authentication: claims.Authentication,
 	}
 
 	accessToken, accessExpTime, err := tc.GenerateAccessToken()
@@ -376,14 +659,26 @@ func (h *Handler) RefreshToken(ctx context.Context, req *userpb.RefreshTokenRequ
 	newRefreshToken, _, err := tc.GenerateRefreshToken()
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to generate token")
-	} else {
-		if revokeErr := h.service.RevokeRefreshToken(userID, refreshToken); revokeErr != nil {
-			return nil, status.Errorf(codes.Internal, "failed to generate token")
-		}
+	}
 
-		if storeErr := h.service.StoreRefreshToken(userID, newRefreshToken); storeErr != nil {
-			return nil, status.Errorf(codes.Internal, "failed to generate token")
+	// the check and the rotation are one step, a concurrent refresh with the same token counts as a reuse
+	rotated, err := h.service.RotateRefreshToken(userID, tc.FamilyID, refreshToken, newRefreshToken)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate token")
+	}
+	if !rotated {
+		reused, err := h.service.DetectRefreshTokenReuse(userID, claims.FamilyID, claims.TokenID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "internal server error")
 		}
+		if reused {
+			return nil, status.Errorf(codes.Unauthenticated, "refresh token reuse detected, please login again")
+		}
+		return nil, status.Errorf(codes.Unauthenticated, "refresh token has been revoked")
+	}
+
+	if sessionErr := h.service.RecordSession(h.clientSession(ctx, userID, tc.FamilyID)); sessionErr != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store session")
 	}
 
 	return &userpb.RefreshTokenResponse{
@@ -410,6 +705,23 @@ func (h *Handler) Logout(ctx context.Context, req *userpb.RefreshTokenRequest) (
 		if revokeErr := h.service.RevokeRefreshToken(userID, refreshToken); revokeErr != nil {
 			return nil, status.Errorf(codes.Internal, "failed to revoke refresh token: %v", revokeErr)
 		}
+
+		// ending the family keeps a replayed older token from being mistaken for a reuse
+		if claims.FamilyID != "" {
+			if revokeErr := h.service.RevokeRefreshTokenFamily(userID, claims.FamilyID); revokeErr != nil {
+				return nil, status.Errorf(codes.Internal, "failed to revoke refresh token: %v", revokeErr)
+			}
+		}
+	}
+
+	// the access token of the caller would otherwise stay valid until it expires
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md["authorization"]) > 0 {
+		accessToken := md["authorization"][0]
+		if _, err := jwt.ValidateAccessToken(accessToken); err == nil {
+			if revokeErr := h.tService.RevokeToken(accessToken); revokeErr != nil {
+				return nil, status.Errorf(codes.Internal, "failed to revoke access token: %v", revokeErr)
+			}
+		}
 	}
 
 	return &userpb.LogoutResponse{
@@ -417,3 +729,101 @@ func (h *Handler) Logout(ctx context.Context, req *userpb.RefreshTokenRequest) (
 		Message: "logged out successfully",
 	}, nil
 }
+
+func (h *Handler) LogoutAll(ctx context.Context, req *userpb.LogoutAllRequest) (*userpb.LogoutResponse, error) {
+	claims, err := h.currentClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := req.GetUserId()
+	if userID == "" {
+		userID = claims.ID
+	}
+	if userID != claims.ID && !middleware.HasPermissions(claims.Access, permission.LogoutAllUsers) {
+		return nil, status.Errorf(codes.PermissionDenied, "not allowed to logout other users")
+	}
+
+	if err := h.service.RevokeAllTokens(userID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke tokens: %v", err)
+	}
+
+	return &userpb.LogoutResponse{
+		Success: true,
+		Message: "logged out everywhere",
+	}, nil
+}
+
+func (h *Handler) ListSessions(ctx context.Context, req *userpb.ListSessionsRequest) (*userpb.ListSessionsResponse, error) {
+	claims, err := h.currentClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := h.service.ListSessions(claims.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list sessions: %v", err)
+	}
+
+	var pbSessions []*userpb.Session
+	for _, s := range sessions {
+		pbSessions = append(pbSessions, toSessionPb(s, claims.SessionID))
+	}
+
+	return &userpb.ListSessionsResponse{
+		Sessions: pbSessions,
+	}, nil
+}
+
+func (h *Handler) RevokeSession(ctx context.Context, req *userpb.RevokeSessionRequest) (*userpb.RevokeSessionResponse, error) {
+	claims, err := h.currentClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetSessionId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "session id is required")
+	}
+
+	if err := h.service.RevokeSession(claims.ID, req.GetSessionId()); err != nil {
+		return nil, status.Errorf(codes.NotFound, "failed to revoke session: %v", err)
+	}
+
+	// access tokens of the session would otherwise stay valid until they expire
+	if err := h.tService.RevokeSession(req.GetSessionId()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke session tokens: %v", err)
+	}
+
+	return &userpb.RevokeSessionResponse{
+		Success: true,
+		Message: "session revoked",
+	}, nil
+}
+
+func (h *Handler) RevokeAllOtherSessions(ctx context.Context, req *userpb.RevokeAllOtherSessionsRequest) (*userpb.RevokeAllOtherSessionsResponse, error) {
+	claims, err := h.currentClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.SessionID == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "access token does not belong to a session, please login again")
+	}
+
+	revoked, err := h.service.RevokeAllOtherSessions(claims.ID, claims.SessionID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke sessions: %v", err)
+	}
+
+	for _, sessionID := range revoked {
+		if err := h.tService.RevokeSession(sessionID); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to revoke session tokens: %v", err)
+		}
+	}
+
+	return &userpb.RevokeAllOtherSessionsResponse{
+		Success: true,
+		Message: "other sessions revoked",
+		Revoked: int32(len(revoked)),
+	}, nil
+}
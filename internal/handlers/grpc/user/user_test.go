@@ -0,0 +1,47 @@
+package usergrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestClientIP(t *testing.T) {
+	_, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+
+	tests := []struct {
+		name    string
+		peer    string
+		forward []string
+		want    string
+	}{
+		{"direct", "203.0.113.7:4000", nil, "203.0.113.7"},
+		{"spoofed by a client", "203.0.113.7:4000", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"through a proxy", "10.0.0.2:4000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"spoofed through a proxy", "10.0.0.2:4000", []string{"192.0.2.9, 198.51.100.1"}, "198.51.100.1"},
+		{"through two proxies", "10.0.0.2:4000", []string{"198.51.100.1, 10.0.0.3"}, "198.51.100.1"},
+		{"proxy without header", "10.0.0.2:4000", nil, "10.0.0.2"},
+		{"garbage from a proxy", "10.0.0.2:4000", []string{"unknown"}, "10.0.0.2"},
+	}
+
+	h := &Handler{proxies: []*net.IPNet{proxies}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := net.ResolveTCPAddr("tcp", tt.peer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+			md := metadata.MD{}
+			if tt.forward != nil {
+				md.Set("x-forwarded-for", tt.forward...)
+			}
+			if got := h.clientIP(ctx, md); got != tt.want {
+				t.Fatalf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
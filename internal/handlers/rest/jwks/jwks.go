@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yasinsaee/go-user-service/internal/context"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+)
+
+// JWKSHandler serves the public keys used to verify issued tokens
+type JWKSHandler struct{}
+
+// NewJWKSHandler creates a new JWKSHandler
+func NewJWKSHandler() *JWKSHandler {
+	return &JWKSHandler{}
+}
+
+// RegisterRoutes registers jwks routes
+func (h *JWKSHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/.well-known/jwks.json", h.Get)
+}
+
+// Get godoc
+// @Summary Get JSON Web Key Set
+// @Description Public keys for verifying access and refresh tokens, matched by the kid header
+// @Tags jwks
+// @Produce json
+// @Success 200 {object} jwt.JSONWebKeySet
+// @Failure 500 {object} map[string]interface{}
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) Get(c echo.Context) error {
+	g := c.(*context.GlobalContext)
+
+	set, err := jwt.JWKS()
+	if err != nil {
+		return g.CreateErrorResponse(http.StatusInternalServerError, err, "failed to load verification keys")
+	}
+
+	// the set is a standard document, consumers expect it without our response envelope
+	g.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	return g.JSON(http.StatusOK, set)
+}
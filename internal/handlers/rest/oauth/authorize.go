@@ -0,0 +1,390 @@
+package handler
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yasinsaee/go-user-service/internal/context"
+	"github.com/yasinsaee/go-user-service/internal/domain/client"
+	"github.com/yasinsaee/go-user-service/internal/domain/mfa"
+	"github.com/yasinsaee/go-user-service/internal/domain/oauth"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+)
+
+//go:embed templates/*.html
+var templates embed.FS
+
+var pages = template.Must(template.ParseFS(templates, "templates/*.html"))
+
+type (
+	// authorizeRequest holds the parameters of an authorization request (RFC 6749 section 4.1.1, RFC 7636)
+	authorizeRequest struct {
+		ResponseType        string
+		ClientID            string
+		RedirectURI         string
+		Scope               string
+		State               string
+		CodeChallenge       string
+		CodeChallengeMethod string
+		Nonce               string
+	}
+
+	// authorizePage is rendered into the login and consent page
+	authorizePage struct {
+		Request    authorizeRequest
+		ClientName string
+		Scopes     []string
+		Providers  []providerLink
+		MFA        bool // the user has a second factor, its code is asked along with the password
+		TrustDays  int  // how long the browser may be trusted, 0 when it cannot be
+		Username   string
+		CSRF       string
+		Error      string
+	}
+
+	// providerLink starts a login at an upstream identity provider
+	providerLink struct {
+		Name string
+		Href template.URL
+	}
+)
+
+// readAuthorizeRequest reads the parameters from the query of a GET or the form of a POST.
+func readAuthorizeRequest(g *context.GlobalContext) authorizeRequest {
+	return authorizeRequest{
+		ResponseType:        g.FormValue("response_type"),
+		ClientID:            g.FormValue("client_id"),
+		RedirectURI:         g.FormValue("redirect_uri"),
+		Scope:               g.FormValue("scope"),
+		State:               g.FormValue("state"),
+		CodeChallenge:       g.FormValue("code_challenge"),
+		CodeChallengeMethod: g.FormValue("code_challenge_method"),
+		Nonce:               g.FormValue("nonce"),
+	}
+}
+
+// authorizeRequestFrom reads the parameters back from values.
+func authorizeRequestFrom(values url.Values) authorizeRequest {
+	return authorizeRequest{
+		ResponseType:        values.Get("response_type"),
+		ClientID:            values.Get("client_id"),
+		RedirectURI:         values.Get("redirect_uri"),
+		Scope:               values.Get("scope"),
+		State:               values.Get("state"),
+		CodeChallenge:       values.Get("code_challenge"),
+		CodeChallengeMethod: values.Get("code_challenge_method"),
+		Nonce:               values.Get("nonce"),
+	}
+}
+
+// values encodes the request as query parameters, see authorizeRequestFrom.
+func (r authorizeRequest) values() url.Values {
+	values := url.Values{}
+	for k, v := range map[string]string{
+		"response_type":         r.ResponseType,
+		"client_id":             r.ClientID,
+		"redirect_uri":          r.RedirectURI,
+		"scope":                 r.Scope,
+		"state":                 r.State,
+		"code_challenge":        r.CodeChallenge,
+		"code_challenge_method": r.CodeChallengeMethod,
+		"nonce":                 r.Nonce,
+	} {
+		if v != "" {
+			values.Set(k, v)
+		}
+	}
+	return values
+}
+
+// authorizeClient finds the client and settles the redirect uri. Until both are known
+// to be valid errors are shown to the user, never redirected to an unchecked uri.
+func (h *OAuthHandler) authorizeClient(r *authorizeRequest) (*client.Client, error) {
+	cl, err := h.cService.GetByClientID(r.ClientID)
+	if err != nil || cl == nil || !cl.IsActive {
+		return nil, errors.New("unknown client")
+	}
+
+	// the redirect uri may only be left out when a single one is registered
+	if r.RedirectURI == "" && len(cl.RedirectURIs) == 1 {
+		r.RedirectURI = cl.RedirectURIs[0]
+	}
+	if !cl.AllowsRedirectURI(r.RedirectURI) {
+		return nil, errors.New("redirect uri is not registered for this client")
+	}
+	return cl, nil
+}
+
+// authorizeScopes checks the rest of the request, failures are reported to the client
+// as an error code and description.
+func authorizeScopes(cl *client.Client, r *authorizeRequest) ([]string, string, string) {
+	if r.ResponseType != "code" {
+		return nil, "unsupported_response_type", "only the code response type is supported"
+	}
+	if r.CodeChallenge == "" || r.CodeChallengeMethod != oauth.CodeChallengeS256 {
+		return nil, "invalid_request", "a S256 code_challenge is required"
+	}
+
+	requested := strings.Fields(r.Scope)
+	if len(requested) == 0 {
+		return cl.Scopes, "", ""
+	}
+
+	// OpenID Connect scopes are open to every client, permissions only when allowed for it
+	identity, permissions := oauth.SplitScopes(requested)
+	if len(permissions) > 0 {
+		if _, ok := cl.GrantScopes(permissions); !ok {
+			return nil, "invalid_scope", "scope is not allowed for this client"
+		}
+	}
+	return append(identity, permissions...), "", ""
+}
+
+// redirectToClient sends the user back to the already validated redirect uri with params and the state.
+func redirectToClient(g *context.GlobalContext, code int, r *authorizeRequest, params url.Values) error {
+	u, err := url.Parse(r.RedirectURI)
+	if err != nil {
+		return renderPage(g, http.StatusBadRequest, "error.html", "invalid redirect uri")
+	}
+
+	query := u.Query()
+	for k, v := range params {
+		query[k] = v
+	}
+	if r.State != "" {
+		query.Set("state", r.State)
+	}
+	u.RawQuery = query.Encode()
+	return g.Redirect(code, u.String())
+}
+
+func redirectError(g *context.GlobalContext, code int, r *authorizeRequest, errCode, description string) error {
+	return redirectToClient(g, code, r, url.Values{
+		"error":             {errCode},
+		"error_description": {description},
+	})
+}
+
+func renderPage(g *context.GlobalContext, code int, name string, data any) error {
+	var buf bytes.Buffer
+	if err := pages.ExecuteTemplate(&buf, name, data); err != nil {
+		return err
+	}
+
+	// the page takes credentials, it must not be framed or cached
+	header := g.Response().Header()
+	header.Set(echo.HeaderXFrameOptions, "DENY")
+	header.Set(echo.HeaderContentSecurityPolicy, "frame-ancestors 'none'")
+	header.Set(echo.HeaderCacheControl, "no-store")
+	return g.HTMLBlob(code, buf.Bytes())
+}
+
+// redirectWithCode issues an authorization code for the signed in user and sends it to the client.
+func (h *OAuthHandler) redirectWithCode(g *context.GlobalContext, status int, cl *client.Client, r *authorizeRequest, scopes []string, userID string, auth jwt.Authentication) error {
+	code := &oauth.AuthorizationCode{
+		ClientID:            cl.ClientID,
+		UserID:              userID,
+		RedirectURI:         r.RedirectURI,
+		Scopes:              scopes,
+		CodeChallenge:       r.CodeChallenge,
+		CodeChallengeMethod: r.CodeChallengeMethod,
+		Nonce:               r.Nonce,
+		Authentication:      auth,
+	}
+	if err := h.aService.IssueCode(code); err != nil {
+		return redirectError(g, status, r, "server_error", "failed to issue authorization code")
+	}
+
+	return redirectToClient(g, status, r, url.Values{"code": {code.Code}})
+}
+
+// providerLinks points the login page to the configured upstream providers, carrying the request along.
+func (h *OAuthHandler) providerLinks(r authorizeRequest) []providerLink {
+	var links []providerLink
+	for _, name := range h.fService.Providers() {
+		links = append(links, providerLink{
+			Name: name,
+			// built from escaped parts only, so it is safe to trust in the template
+			Href: template.URL("/oauth/federated/" + url.PathEscape(name) + "?" + r.values().Encode()),
+		})
+	}
+	return links
+}
+
+// trustedDeviceCookie holds the device token of a browser the user trusts.
+const trustedDeviceCookie = "trusted_device"
+
+func trustedDeviceToken(g *context.GlobalContext) string {
+	cookie, err := g.Cookie(trustedDeviceCookie)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// trustBrowser remembers the browser the user just passed the second factor in.
+func (h *OAuthHandler) trustBrowser(g *context.GlobalContext, u *user.User) error {
+	device := &mfa.TrustedDevice{
+		DeviceName: "browser",
+		UserAgent:  g.Request().UserAgent(),
+		IP:         g.RealIP(),
+	}
+	token, err := h.mService.TrustDevice(u, device)
+	if err != nil {
+		return err
+	}
+
+	g.SetCookie(&http.Cookie{
+		Name:     trustedDeviceCookie,
+		Value:    token,
+		Path:     "/oauth/authorize",
+		Expires:  device.ExpiresAt,
+		HttpOnly: true,
+		Secure:   g.IsTLS(),
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+func csrfToken(g *context.GlobalContext) string {
+	token, _ := g.Get("csrf").(string)
+	return token
+}
+
+// Authorize godoc
+// @Summary Start an authorization code flow
+// @Description Validates the authorization request (RFC 6749 section 4.1.1) and renders the login and consent page, PKCE with S256 is required
+// @Tags oauth
+// @Produce html
+// @Param response_type query string true "code"
+// @Param client_id query string true "Client ID"
+// @Param redirect_uri query string false "One of the client's redirect uris, may be left out when it has only one"
+// @Param scope query string false "Space separated subset of the client's scopes, all of them when empty"
+// @Param state query string false "Opaque value returned to the client"
+// @Param code_challenge query string true "PKCE code challenge"
+// @Param code_challenge_method query string true "S256"
+// @Param nonce query string false "OpenID Connect nonce, echoed in the ID token"
+// @Success 200 {string} string "login and consent page"
+// @Success 302 {string} string "redirect to the client with an error"
+// @Failure 400 {string} string "error page"
+// @Router /oauth/authorize [get]
+func (h *OAuthHandler) Authorize(c echo.Context) error {
+	g := c.(*context.GlobalContext)
+	r := readAuthorizeRequest(g)
+
+	cl, err := h.authorizeClient(&r)
+	if err != nil {
+		return renderPage(g, http.StatusBadRequest, "error.html", err.Error())
+	}
+
+	scopes, errCode, description := authorizeScopes(cl, &r)
+	if errCode != "" {
+		return redirectError(g, http.StatusFound, &r, errCode, description)
+	}
+
+	return renderPage(g, http.StatusOK, "authorize.html", authorizePage{
+		Request:    r,
+		ClientName: cl.Name,
+		Scopes:     scopes,
+		Providers:  h.providerLinks(r),
+		CSRF:       csrfToken(g),
+	})
+}
+
+// Approve godoc
+// @Summary Answer the consent page
+// @Description Checks the user's credentials with the same rules as UserService.Login and redirects back to the client with an authorization code, or with access_denied
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param action formData string true "allow or deny"
+// @Param username formData string true "Username"
+// @Param password formData string true "Password"
+// @Param code formData string false "TOTP or recovery code, asked for when the user has two-factor authentication"
+// @Param trust formData string false "on to trust the browser, later sign-ins in it skip the code"
+// @Success 303 {string} string "redirect to the client"
+// @Failure 400 {string} string "error page"
+// @Failure 401 {string} string "login page with an error"
+// @Router /oauth/authorize [post]
+func (h *OAuthHandler) Approve(c echo.Context) error {
+	g := c.(*context.GlobalContext)
+	r := readAuthorizeRequest(g)
+
+	cl, err := h.authorizeClient(&r)
+	if err != nil {
+		return renderPage(g, http.StatusBadRequest, "error.html", err.Error())
+	}
+
+	scopes, errCode, description := authorizeScopes(cl, &r)
+	if errCode != "" {
+		return redirectError(g, http.StatusSeeOther, &r, errCode, description)
+	}
+
+	if g.FormValue("action") != "allow" {
+		return redirectError(g, http.StatusSeeOther, &r, "access_denied", "the user denied the request")
+	}
+
+	page := authorizePage{
+		Request:    r,
+		ClientName: cl.Name,
+		Scopes:     scopes,
+		Providers:  h.providerLinks(r),
+		Username:   g.FormValue("username"),
+		CSRF:       csrfToken(g),
+	}
+	u, err := h.uService.Login(g.FormValue("username"), g.FormValue("password"))
+	if errors.Is(err, user.ErrDeactivated) {
+		page.Error = "This account is deactivated."
+		return renderPage(g, http.StatusForbidden, "authorize.html", page)
+	}
+	if err != nil {
+		page.Error = "Invalid username or password."
+		return renderPage(g, http.StatusUnauthorized, "authorize.html", page)
+	}
+
+	auth := jwt.NewAuthentication(jwt.AMRPassword)
+
+	// the password is asked again with the code, the page keeps no state between the two
+	if u.MFA.Enabled && !h.mService.IsTrustedDevice(u, trustedDeviceToken(g)) {
+		page.MFA = true
+		page.TrustDays = int(h.mService.TrustedDeviceTTL().Hours() / 24)
+		code := strings.TrimSpace(g.FormValue("code"))
+		if code == "" {
+			page.Error = "Enter the code from your authenticator app or a recovery code."
+			return renderPage(g, http.StatusUnauthorized, "authorize.html", page)
+		}
+		if err := h.mService.Verify(u, code); err != nil {
+			page.Error = "Invalid authentication code."
+			if errors.Is(err, mfa.ErrTooManyAttempts) {
+				page.Error = "Too many invalid codes, try again later."
+			}
+			return renderPage(g, http.StatusUnauthorized, "authorize.html", page)
+		}
+		auth = auth.Add(jwt.AMRTOTP)
+
+		if g.FormValue("trust") == "on" && page.TrustDays > 0 {
+			if err := h.trustBrowser(g, u); err != nil {
+				return redirectError(g, http.StatusSeeOther, &r, "server_error", "failed to trust the browser")
+			}
+		}
+	}
+
+	// the page cannot change passwords, the user changes it in the app and comes back
+	switch h.uService.PasswordChangeReason(u) {
+	case user.PasswordExpired:
+		page.Error = "Your password has expired, change it before signing in."
+		return renderPage(g, http.StatusForbidden, "authorize.html", page)
+	case user.PasswordChangeRequired:
+		page.Error = "You have to change your password before signing in."
+		return renderPage(g, http.StatusForbidden, "authorize.html", page)
+	}
+
+	return h.redirectWithCode(g, http.StatusSeeOther, cl, &r, scopes, u.ID.Hex(), auth)
+}
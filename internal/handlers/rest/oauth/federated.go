@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yasinsaee/go-user-service/internal/context"
+	"github.com/yasinsaee/go-user-service/internal/domain/federation"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+	"github.com/yasinsaee/go-user-service/pkg/logger"
+)
+
+// FederatedLogin godoc
+// @Summary Sign in at an upstream identity provider
+// @Description Validates the authorization request like /oauth/authorize and redirects to the provider, the request is resumed by the callback
+// @Tags oauth
+// @Produce html
+// @Param provider path string true "Name of the provider, one of OIDC_PROVIDERS"
+// @Success 302 {string} string "redirect to the provider or to the client with an error"
+// @Failure 400 {string} string "error page"
+// @Failure 404 {string} string "error page"
+// @Router /oauth/federated/{provider} [get]
+func (h *OAuthHandler) FederatedLogin(c echo.Context) error {
+	g := c.(*context.GlobalContext)
+	r := readAuthorizeRequest(g)
+
+	cl, err := h.authorizeClient(&r)
+	if err != nil {
+		return renderPage(g, http.StatusBadRequest, "error.html", err.Error())
+	}
+	if _, errCode, description := authorizeScopes(cl, &r); errCode != "" {
+		return redirectError(g, http.StatusFound, &r, errCode, description)
+	}
+
+	name := g.Param("provider")
+	state := &federation.LoginState{
+		RedirectURI: baseURL(g) + "/oauth/federated/" + url.PathEscape(name) + "/callback",
+		Request:     r.values(),
+	}
+	authURL, err := h.fService.AuthURL(g.Request().Context(), name, state)
+	if err == federation.ErrUnknownProvider {
+		return renderPage(g, http.StatusNotFound, "error.html", err.Error())
+	}
+	if err != nil {
+		logger.Error("federated login: ", err.Error())
+		return redirectError(g, http.StatusFound, &r, "temporarily_unavailable", "the identity provider is unavailable")
+	}
+
+	return g.Redirect(http.StatusFound, authURL)
+}
+
+// FederatedCallback godoc
+// @Summary Finish a sign in at an upstream identity provider
+// @Description Redeems the provider's code, verifies its ID token against the provider's keys, links or provisions the user by verified email and redirects back to the client with our own authorization code
+// @Tags oauth
+// @Produce html
+// @Param provider path string true "Name of the provider"
+// @Param code query string false "Code of the provider"
+// @Param state query string true "State of the login"
+// @Success 302 {string} string "redirect to the client"
+// @Failure 400 {string} string "error page"
+// @Router /oauth/federated/{provider}/callback [get]
+func (h *OAuthHandler) FederatedCallback(c echo.Context) error {
+	g := c.(*context.GlobalContext)
+
+	u, state, err := h.fService.Callback(g.Request().Context(), g.QueryParam("state"), g.QueryParam("code"))
+	if state == nil {
+		return renderPage(g, http.StatusBadRequest, "error.html", "the sign in has expired, please start again")
+	}
+
+	r := authorizeRequestFrom(state.Request)
+	cl, cerr := h.authorizeClient(&r)
+	if cerr != nil {
+		return renderPage(g, http.StatusBadRequest, "error.html", cerr.Error())
+	}
+	scopes, errCode, description := authorizeScopes(cl, &r)
+	if errCode != "" {
+		return redirectError(g, http.StatusFound, &r, errCode, description)
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, federation.ErrEmailNotVerified), errors.Is(err, federation.ErrNoAccount), errors.Is(err, federation.ErrLoginDenied),
+			errors.Is(err, user.ErrDeactivated):
+			return redirectError(g, http.StatusFound, &r, "access_denied", err.Error())
+		default:
+			logger.Error("federated login: ", err.Error())
+			return redirectError(g, http.StatusFound, &r, "access_denied", "the sign in at the identity provider failed")
+		}
+	}
+
+	// the provider cannot vouch for our second factor, the user signs in here instead
+	if u.MFA.Enabled {
+		return renderPage(g, http.StatusUnauthorized, "authorize.html", authorizePage{
+			Request:    r,
+			ClientName: cl.Name,
+			Scopes:     scopes,
+			MFA:        true,
+			Username:   u.Username,
+			CSRF:       csrfToken(g),
+			Error:      "This account uses two-factor authentication, sign in with your password and code.",
+		})
+	}
+
+	// as on the login page, the password is changed in the app first
+	if description := federatedPasswordError(h.uService.PasswordChangeReason(u)); description != "" {
+		return redirectError(g, http.StatusFound, &r, "access_denied", description)
+	}
+
+	return h.redirectWithCode(g, http.StatusFound, cl, &r, scopes, u.ID.Hex(), jwt.NewAuthentication(jwt.AMRFederated))
+}
+
+// federatedPasswordError returns why a federated sign-in has to wait for a password change,
+// or "" when it does not. An expired password does not count, the sign-in does not use it
+// and accounts created by the sign-in have a password nobody knows.
+func federatedPasswordError(reason string) string {
+	if reason == user.PasswordChangeRequired {
+		return "the password has to be changed before signing in"
+	}
+	return ""
+}
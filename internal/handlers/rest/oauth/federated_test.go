@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+)
+
+func TestFederatedSignInIgnoresPasswordExpiry(t *testing.T) {
+	if got := federatedPasswordError(user.PasswordExpired); got != "" {
+		t.Fatalf("expected an expired password not to stop a federated sign-in, got %q", got)
+	}
+	if got := federatedPasswordError(""); got != "" {
+		t.Fatalf("expected no error for a fine password, got %q", got)
+	}
+	if got := federatedPasswordError(user.PasswordChangeRequired); got == "" {
+		t.Fatal("expected a required password change to stop a federated sign-in")
+	}
+}
@@ -0,0 +1,456 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	echomw "github.com/labstack/echo/v4/middleware"
+	"github.com/yasinsaee/go-user-service/internal/context"
+	"github.com/yasinsaee/go-user-service/internal/domain/client"
+	"github.com/yasinsaee/go-user-service/internal/domain/federation"
+	"github.com/yasinsaee/go-user-service/internal/domain/mfa"
+	"github.com/yasinsaee/go-user-service/internal/domain/oauth"
+	"github.com/yasinsaee/go-user-service/internal/domain/permission"
+	"github.com/yasinsaee/go-user-service/internal/domain/role"
+	"github.com/yasinsaee/go-user-service/internal/domain/token"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/internal/middleware"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+	"github.com/yasinsaee/go-user-service/pkg/util"
+)
+
+// OAuthHandler serves the OAuth 2.0 endpoints used by registered clients
+type OAuthHandler struct {
+	tService token.TokenService
+	cService client.ClientService
+	aService oauth.AuthorizationService
+	uService user.UserService
+	rService role.RoleService
+	pService permission.PermissionService
+	fService federation.FederationService
+	mService mfa.MFAService
+}
+
+// IntrospectionResponse is the RFC 7662 introspection document
+type IntrospectionResponse struct {
+	Active    bool         `json:"active"`
+	TokenType string       `json:"token_type,omitempty"`
+	Sub       string       `json:"sub,omitempty"`
+	Username  string       `json:"username,omitempty"`
+	Roles     []string     `json:"roles,omitempty"`
+	Access    []string     `json:"access,omitempty"`
+	Jti       string       `json:"jti,omitempty"`
+	Iss       string       `json:"iss,omitempty"`
+	Aud       jwt.Audience `json:"aud,omitempty"`
+	Iat       int64        `json:"iat,omitempty"`
+	Exp       int64        `json:"exp,omitempty"`
+	Principal string       `json:"principal,omitempty"`
+	ClientID  string       `json:"client_id,omitempty"`
+	jwt.Authentication
+}
+
+// TokenResponse is the RFC 6749 access token response
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Scope        string `json:"scope,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"` // OpenID Connect, when the openid scope was granted
+}
+
+// ErrorResponse is the RFC 6749 error response of the token endpoint
+type ErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// NewOAuthHandler creates a new OAuthHandler
+func NewOAuthHandler(tService token.TokenService, cService client.ClientService, aService oauth.AuthorizationService,
+	uService user.UserService, rService role.RoleService, pService permission.PermissionService, fService federation.FederationService, mService mfa.MFAService) *OAuthHandler {
+	return &OAuthHandler{
+		tService: tService,
+		cService: cService,
+		aService: aService,
+		uService: uService,
+		rService: rService,
+		pService: pService,
+		fService: fService,
+		mService: mService,
+	}
+}
+
+// RegisterRoutes registers oauth routes
+func (h *OAuthHandler) RegisterRoutes(e *echo.Echo) {
+	// the login form is protected by a double submit cookie
+	csrf := echomw.CSRFWithConfig(echomw.CSRFConfig{
+		TokenLookup:    "form:csrf",
+		CookiePath:     "/oauth/authorize",
+		CookieHTTPOnly: true,
+		CookieSameSite: http.SameSiteLaxMode,
+	})
+
+	g := e.Group("/oauth")
+	g.GET("/authorize", h.Authorize, csrf)
+	g.POST("/authorize", h.Approve, csrf)
+	g.POST("/token", h.Token)
+	g.POST("/introspect", h.Introspect)
+	g.GET("/federated/:provider", h.FederatedLogin)
+	g.GET("/federated/:provider/callback", h.FederatedCallback, csrf)
+
+	e.GET("/.well-known/openid-configuration", h.Discovery)
+	e.GET("/userinfo", h.UserInfo)
+	e.POST("/userinfo", h.UserInfo)
+}
+
+// authenticateClient accepts HTTP Basic credentials or client_id/client_secret form fields.
+func (h *OAuthHandler) authenticateClient(g *context.GlobalContext) (*client.Client, error) {
+	clientID, secret, ok := util.ParseBasicAuth(g.Request().Header.Get(echo.HeaderAuthorization))
+	if !ok {
+		clientID, secret = g.FormValue("client_id"), g.FormValue("client_secret")
+	}
+	return h.cService.Authenticate(clientID, secret)
+}
+
+// tokenClient identifies the client at the token endpoint. Confidential clients authenticate,
+// public clients only name themselves, what they redeem is bound to them by PKCE or the refresh token.
+func (h *OAuthHandler) tokenClient(g *context.GlobalContext) (*client.Client, error) {
+	if _, _, ok := util.ParseBasicAuth(g.Request().Header.Get(echo.HeaderAuthorization)); ok || g.FormValue("client_secret") != "" {
+		return h.authenticateClient(g)
+	}
+
+	cl, err := h.cService.GetByClientID(g.FormValue("client_id"))
+	if err != nil || cl == nil || !cl.IsActive || !cl.Public {
+		return nil, errors.New("invalid client credentials")
+	}
+	return cl, nil
+}
+
+// userAccess returns the role and permission names the user currently has.
+func (h *OAuthHandler) userAccess(u *user.User) (roles []string, permissions []string) {
+	for _, roleID := range u.Roles {
+		r, err := h.rService.GetByID(roleID)
+		if err != nil {
+			continue
+		}
+		roles = append(roles, r.Name)
+		for _, permissionID := range r.Permissions {
+			p, err := h.pService.GetByID(permissionID)
+			if err != nil {
+				continue
+			}
+			permissions = append(permissions, p.Name)
+		}
+	}
+	return
+}
+
+// scopedAccess narrows the user's permissions down to the scopes granted to the client.
+func scopedAccess(permissions, scopes []string) []string {
+	if slices.Contains(scopes, middleware.AnyPermission) {
+		return permissions
+	}
+	if slices.Contains(permissions, middleware.AnyPermission) {
+		return scopes
+	}
+
+	access := []string{}
+	for _, p := range permissions {
+		if slices.Contains(scopes, p) {
+			access = append(access, p)
+		}
+	}
+	return access
+}
+
+// errRefreshTokenNotCurrent is returned by issueUserTokens when the refresh token it was to
+// replace was rotated or revoked in the meantime.
+var errRefreshTokenNotCurrent = errors.New("refresh token is no longer current")
+
+// issueUserTokens issues a token pair to the client acting for the user, continuing familyID
+// and replacing its refresh token previous when it is a refresh, or starting a new session
+// otherwise. An ID token is added for the openid scope, code is the redeemed authorization
+// code and nil on a refresh. auth tells how the user signed in to the session.
+func (h *OAuthHandler) issueUserTokens(g *context.GlobalContext, cl *client.Client, u *user.User, scopes []string, familyID, previous string, code *oauth.AuthorizationCode, auth jwt.Authentication) (*TokenResponse, error) {
+	roles, permissions := h.userAccess(u)
+	tc := jwt.TokenConfig{
+		ID:             u.ID.Hex(),
+		Username:       u.Username,
+		Roles:          roles,
+		Access:         scopedAccess(permissions, scopes),
+		FamilyID:       familyID,
+		ClientID:       cl.ClientID,
+		Scopes:         scopes,
+		Authentication: auth,
+	}
+
+	accessToken, exp, err := tc.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, _, err := tc.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if previous == "" {
+		if err := h.uService.StoreRefreshToken(u.ID.Hex(), tc.FamilyID, refreshToken); err != nil {
+			return nil, err
+		}
+	} else {
+		rotated, err := h.uService.RotateRefreshToken(u.ID.Hex(), tc.FamilyID, previous, refreshToken)
+		if err != nil {
+			return nil, err
+		}
+		if !rotated {
+			return nil, errRefreshTokenNotCurrent
+		}
+	}
+	session := &user.Session{
+		ID:         tc.FamilyID,
+		UserID:     u.ID.Hex(),
+		DeviceName: cl.Name,
+		UserAgent:  g.Request().UserAgent(),
+		IP:         g.RealIP(),
+	}
+	if err := h.uService.RecordSession(session); err != nil {
+		return nil, err
+	}
+
+	resp := &TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(time.Until(exp).Seconds()),
+		Scope:        strings.Join(scopes, " "),
+		RefreshToken: refreshToken,
+	}
+
+	if slices.Contains(scopes, oauth.ScopeOpenID) {
+		idc := jwt.IDTokenConfig{
+			Subject:        u.ID.Hex(),
+			ClientID:       cl.ClientID,
+			SessionID:      tc.FamilyID,
+			Authentication: auth,
+			UserInfo:       userInfo(u, scopes),
+		}
+		if code != nil {
+			idc.Nonce = code.Nonce
+		}
+		if resp.IDToken, _, err = idc.GenerateIDToken(); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// Token godoc
+// @Summary Issue tokens
+// @Description Token endpoint (RFC 6749 section 3.2) for the client_credentials, authorization_code (with PKCE) and refresh_token grants. Client credentials tokens carry the granted scopes as permissions and cannot be refreshed, tokens of the other grants carry the user's permissions narrowed down to the granted scopes
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "client_credentials, authorization_code or refresh_token"
+// @Param scope formData string false "Space separated subset of the client's scopes, all of them when empty"
+// @Param code formData string false "Authorization code, for authorization_code"
+// @Param redirect_uri formData string false "Redirect uri of the authorization request, for authorization_code"
+// @Param code_verifier formData string false "PKCE code verifier, for authorization_code"
+// @Param refresh_token formData string false "Refresh token, for refresh_token"
+// @Param client_id formData string false "Client ID of a public client"
+// @Success 200 {object} TokenResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /oauth/token [post]
+func (h *OAuthHandler) Token(c echo.Context) error {
+	g := c.(*context.GlobalContext)
+	g.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+
+	switch g.FormValue("grant_type") {
+	case "client_credentials":
+		return h.clientCredentials(g)
+	case "authorization_code":
+		return h.authorizationCode(g)
+	case "refresh_token":
+		return h.refreshToken(g)
+	default:
+		return g.JSON(http.StatusBadRequest, ErrorResponse{Error: "unsupported_grant_type"})
+	}
+}
+
+// -- start grants
+func invalidClient(g *context.GlobalContext) error {
+	g.Response().Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+	return g.JSON(http.StatusUnauthorized, ErrorResponse{Error: "invalid_client"})
+}
+
+func (h *OAuthHandler) clientCredentials(g *context.GlobalContext) error {
+	cl, err := h.authenticateClient(g)
+	if err != nil {
+		return invalidClient(g)
+	}
+	if cl.Public {
+		return g.JSON(http.StatusBadRequest, ErrorResponse{Error: "unauthorized_client", ErrorDescription: "public clients cannot use the client credentials grant"})
+	}
+
+	scopes, ok := cl.GrantScopes(strings.Fields(g.FormValue("scope")))
+	if !ok {
+		return g.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_scope", ErrorDescription: "scope is not allowed for this client"})
+	}
+
+	tc := jwt.ClientTokenConfig{
+		ClientID: cl.ClientID,
+		Scopes:   scopes,
+	}
+	accessToken, exp, err := tc.GenerateAccessToken()
+	if err != nil {
+		return g.JSON(http.StatusInternalServerError, ErrorResponse{Error: "server_error"})
+	}
+
+	return g.JSON(http.StatusOK, TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(time.Until(exp).Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	})
+}
+
+func (h *OAuthHandler) authorizationCode(g *context.GlobalContext) error {
+	cl, err := h.tokenClient(g)
+	if err != nil {
+		return invalidClient(g)
+	}
+
+	code, err := h.aService.RedeemCode(g.FormValue("code"), cl.ClientID, g.FormValue("redirect_uri"), g.FormValue("code_verifier"))
+	if err == oauth.ErrInvalidGrant {
+		return g.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_grant", ErrorDescription: err.Error()})
+	}
+	if err != nil {
+		return g.JSON(http.StatusInternalServerError, ErrorResponse{Error: "server_error"})
+	}
+
+	u, err := h.uService.GetByID(code.UserID)
+	if err != nil || u == nil {
+		return g.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_grant", ErrorDescription: "user not found"})
+	}
+	if !u.Active() {
+		return g.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_grant", ErrorDescription: user.ErrDeactivated.Error()})
+	}
+
+	resp, err := h.issueUserTokens(g, cl, u, code.Scopes, "", "", code, code.Authentication)
+	if err != nil {
+		return g.JSON(http.StatusInternalServerError, ErrorResponse{Error: "server_error"})
+	}
+	return g.JSON(http.StatusOK, resp)
+}
+
+func (h *OAuthHandler) refreshToken(g *context.GlobalContext) error {
+	cl, err := h.tokenClient(g)
+	if err != nil {
+		return invalidClient(g)
+	}
+
+	refreshToken := g.FormValue("refresh_token")
+	claims, err := jwt.ValidateRefreshToken(refreshToken)
+	if err != nil || claims.ClientID != cl.ClientID {
+		return g.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_grant", ErrorDescription: "invalid or expired refresh token"})
+	}
+
+	userID := claims.ID
+
+	// a refresh may narrow the scopes down, never widen them (RFC 6749 section 6)
+	scopes := claims.Scopes
+	if requested := strings.Fields(g.FormValue("scope")); len(requested) > 0 {
+		for _, scope := range requested {
+			if !slices.Contains(claims.Scopes, scope) {
+				return g.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_scope", ErrorDescription: "scope was not granted"})
+			}
+		}
+		scopes = requested
+	}
+
+	u, err := h.uService.GetByID(userID)
+	if err != nil || u == nil {
+		return g.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_grant", ErrorDescription: "user not found"})
+	}
+	if !u.Active() {
+		return g.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_grant", ErrorDescription: user.ErrDeactivated.Error()})
+	}
+
+	// the check and the rotation are one step, a concurrent refresh with the same token counts as a reuse
+	resp, err := h.issueUserTokens(g, cl, u, scopes, claims.FamilyID, refreshToken, nil, claims.Authentication)
+	if errors.Is(err, errRefreshTokenNotCurrent) {
+		reused, err := h.uService.DetectRefreshTokenReuse(userID, claims.FamilyID, claims.TokenID)
+		if err != nil {
+			return g.JSON(http.StatusInternalServerError, ErrorResponse{Error: "server_error"})
+		}
+		if reused {
+			return g.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_grant", ErrorDescription: "refresh token reuse detected"})
+		}
+		return g.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_grant", ErrorDescription: "refresh token has been revoked"})
+	}
+	if err != nil {
+		return g.JSON(http.StatusInternalServerError, ErrorResponse{Error: "server_error"})
+	}
+	return g.JSON(http.StatusOK, resp)
+}
+
+//-- end grants
+
+// Introspect godoc
+// @Summary Introspect a token
+// @Description Reports whether an access or refresh token is active (RFC 7662), the caller authenticates as a registered client
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Token to introspect"
+// @Param token_type_hint formData string false "access_token or refresh_token"
+// @Success 200 {object} IntrospectionResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /oauth/introspect [post]
+func (h *OAuthHandler) Introspect(c echo.Context) error {
+	g := c.(*context.GlobalContext)
+
+	if _, err := h.authenticateClient(g); err != nil {
+		g.Response().Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+		return g.CreateErrorResponse(http.StatusUnauthorized, err, "invalid client credentials")
+	}
+
+	signed := g.FormValue("token")
+	if signed == "" {
+		return g.CreateErrorResponse(http.StatusBadRequest, nil, "token is required")
+	}
+
+	result, err := h.tService.Introspect(signed, token.TypeFromHint(g.FormValue("token_type_hint")))
+	if err != nil {
+		return g.CreateErrorResponse(http.StatusInternalServerError, err, "failed to introspect token")
+	}
+
+	// the document is standard, consumers expect it without our response envelope
+	g.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	if !result.Active {
+		return g.JSON(http.StatusOK, IntrospectionResponse{Active: false})
+	}
+
+	return g.JSON(http.StatusOK, IntrospectionResponse{
+		Active:         true,
+		TokenType:      string(result.TokenType),
+		Sub:            result.Subject,
+		Username:       result.Username,
+		Roles:          result.Roles,
+		Access:         result.Access,
+		Jti:            result.TokenID,
+		Iss:            result.Issuer,
+		Aud:            result.Audience,
+		Iat:            result.IssuedAt.Unix(),
+		Exp:            result.ExpiresAt.Unix(),
+		Principal:      string(result.Principal),
+		ClientID:       result.ClientID,
+		Authentication: result.Authentication,
+	})
+}
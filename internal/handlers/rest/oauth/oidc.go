@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yasinsaee/go-user-service/internal/context"
+	"github.com/yasinsaee/go-user-service/internal/domain/oauth"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/internal/middleware"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+)
+
+type (
+	// ProviderMetadata is the OpenID Connect discovery document
+	ProviderMetadata struct {
+		Issuer                            string   `json:"issuer"`
+		AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+		TokenEndpoint                     string   `json:"token_endpoint"`
+		UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+		JWKSURI                           string   `json:"jwks_uri"`
+		IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+		ScopesSupported                   []string `json:"scopes_supported"`
+		ResponseTypesSupported            []string `json:"response_types_supported"`
+		GrantTypesSupported               []string `json:"grant_types_supported"`
+		SubjectTypesSupported             []string `json:"subject_types_supported"`
+		IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+		TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+		CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+		ClaimsSupported                   []string `json:"claims_supported"`
+	}
+
+	// UserInfoResponse holds the claims about the user of the access token
+	UserInfoResponse struct {
+		Sub string `json:"sub"`
+		jwt.UserInfo
+	}
+)
+
+// userInfo maps the user to the standard claims covered by scopes.
+func userInfo(u *user.User, scopes []string) jwt.UserInfo {
+	var info jwt.UserInfo
+	if slices.Contains(scopes, oauth.ScopeProfile) {
+		info.Name = strings.TrimSpace(u.FirstName + " " + u.LastName)
+		info.GivenName = u.FirstName
+		info.FamilyName = u.LastName
+		info.PreferredUsername = u.Username
+		info.Picture = u.ProfileImage
+		if !u.UpdatedAt.IsZero() {
+			info.UpdatedAt = u.UpdatedAt.Unix()
+		}
+	}
+	if slices.Contains(scopes, oauth.ScopeEmail) {
+		info.Email = u.Email
+	}
+	if slices.Contains(scopes, oauth.ScopePhone) {
+		info.PhoneNumber = u.PhoneNumber
+	}
+	return info
+}
+
+// baseURL is where the endpoints are served, the issuer when it is an URL as OpenID Connect expects.
+func baseURL(g *context.GlobalContext) string {
+	issuer := jwt.Issuer()
+	if strings.HasPrefix(issuer, "https://") || strings.HasPrefix(issuer, "http://") {
+		return strings.TrimSuffix(issuer, "/")
+	}
+	return g.Scheme() + "://" + g.Request().Host
+}
+
+// Discovery godoc
+// @Summary OpenID Connect discovery
+// @Description Provider metadata (OpenID Connect Discovery 1.0), the issuer is JWT_ISSUER which should be the public URL of the service
+// @Tags oidc
+// @Produce json
+// @Success 200 {object} ProviderMetadata
+// @Failure 404 {object} map[string]interface{}
+// @Router /.well-known/openid-configuration [get]
+func (h *OAuthHandler) Discovery(c echo.Context) error {
+	g := c.(*context.GlobalContext)
+	base := baseURL(g)
+
+	// relying parties only have to support RS256, a provider without it is none they can use
+	algorithms := jwt.SigningAlgorithms()
+	if !slices.Contains(algorithms, jwt.AlgorithmRS256) {
+		return g.CreateErrorResponse(http.StatusNotFound, nil, "OpenID Connect needs an RS256 signing key, set JWT_ALGORITHM=RS256")
+	}
+
+	return g.JSON(http.StatusOK, ProviderMetadata{
+		Issuer:                            jwt.Issuer(),
+		AuthorizationEndpoint:             base + "/oauth/authorize",
+		TokenEndpoint:                     base + "/oauth/token",
+		UserInfoEndpoint:                  base + "/userinfo",
+		JWKSURI:                           base + "/.well-known/jwks.json",
+		IntrospectionEndpoint:             base + "/oauth/introspect",
+		ScopesSupported:                   oauth.IdentityScopes,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code", "refresh_token", "client_credentials"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  algorithms,
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{oauth.CodeChallengeS256},
+		ClaimsSupported: []string{
+			"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "azp", "sid",
+			"name", "given_name", "family_name", "preferred_username", "picture", "updated_at",
+			"email", "phone_number",
+		},
+	})
+}
+
+// UserInfo godoc
+// @Summary OpenID Connect userinfo
+// @Description Claims about the user of the bearer access token. Tokens issued to a client need the openid scope and only get the claims of their scopes
+// @Tags oidc
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} UserInfoResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /userinfo [get]
+// @Router /userinfo [post]
+func (h *OAuthHandler) UserInfo(c echo.Context) error {
+	g := c.(*context.GlobalContext)
+
+	claims, ok := middleware.ClaimsFromContext(g.Request().Context())
+	if !ok {
+		g.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		return g.CreateErrorResponse(http.StatusUnauthorized, nil, "access token is required")
+	}
+
+	// tokens of the user's own logins see everything, a client sees what it was granted
+	scopes := oauth.IdentityScopes
+	if claims.ClientID != "" {
+		if !slices.Contains(claims.Scopes, oauth.ScopeOpenID) {
+			g.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="insufficient_scope", scope="openid"`)
+			return g.CreateErrorResponse(http.StatusForbidden, nil, "the openid scope is required")
+		}
+		scopes = claims.Scopes
+	}
+
+	u, err := h.uService.GetByID(claims.ID)
+	if err != nil || u == nil {
+		g.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		return g.CreateErrorResponse(http.StatusUnauthorized, err, "user not found")
+	}
+
+	g.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return g.JSON(http.StatusOK, UserInfoResponse{
+		Sub:      u.ID.Hex(),
+		UserInfo: userInfo(u, scopes),
+	})
+}
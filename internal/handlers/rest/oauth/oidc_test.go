@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yasinsaee/go-user-service/internal/context"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+)
+
+// initKeys signs tokens with a fresh key, RSA or Ed25519.
+func initKeys(t *testing.T, useRSA bool) {
+	t.Helper()
+
+	var private, public any
+	if useRSA {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		private, public = key, &key.PublicKey
+	} else {
+		pub, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		private, public = key, pub
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := jwt.Init(jwt.JWTConfig{
+		PrivateKey:      pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}),
+		PublicKey:       pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}),
+		Issuer:          "https://auth.example.com",
+		AccessTokenExp:  1,
+		RefreshTokenExp: 1,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func discover(t *testing.T) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	if err := new(OAuthHandler).Discovery(&context.GlobalContext{Context: c}); err != nil {
+		t.Fatal(err)
+	}
+	return rec
+}
+
+func TestDiscoveryAdvertisesRS256(t *testing.T) {
+	initKeys(t, true)
+
+	rec := discover(t)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var metadata ProviderMetadata
+	if err := json.Unmarshal(rec.Body.Bytes(), &metadata); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(metadata.IDTokenSigningAlgValuesSupported, jwt.AlgorithmRS256) {
+		t.Fatalf("id_token_signing_alg_values_supported = %v, want RS256 in it", metadata.IDTokenSigningAlgValuesSupported)
+	}
+}
+
+func TestDiscoveryRefusedWithoutRS256Key(t *testing.T) {
+	initKeys(t, false)
+
+	if rec := discover(t); rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404 without an RS256 key", rec.Code)
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+)
+
+type claimsKey struct{}
+
+// ContextWithClaims returns a copy of ctx carrying the caller's access token claims.
+func ContextWithClaims(ctx context.Context, claims *jwt.JWTClaims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims put in the context by the interceptor.
+func ClaimsFromContext(ctx context.Context) (*jwt.JWTClaims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*jwt.JWTClaims)
+	return claims, ok && claims != nil
+}
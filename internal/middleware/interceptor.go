@@ -2,51 +2,148 @@ package middleware
 
 import (
 	"context"
+	"strconv"
 	"strings"
 
-	"github.com/golang-jwt/jwt"
 	jwt2 "github.com/yasinsaee/go-user-service/pkg/jwt"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
-func AuthInterceptor() grpc.UnaryServerInterceptor {
+// APIKeyAuthenticator resolves an api key into claims shaped like its owner's access token.
+type APIKeyAuthenticator interface {
+	AuthenticateKey(key string) (*jwt2.JWTClaims, error)
+}
+
+// AuthInterceptor authorizes unary calls against the policy of their method.
+// Callers present an access token in the authorization metadata or an api key in x-api-key.
+func AuthInterceptor(policies Policies, keys APIKeyAuthenticator) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		if strings.Contains(info.FullMethod, "Login") {
-			return handler(ctx, req)
+		newCtx, err := authorize(ctx, policies, keys, info.FullMethod)
+		if err != nil {
+			return nil, err
 		}
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Errorf(codes.Unauthenticated, "missing metadata")
+		return handler(newCtx, req)
+	}
+}
+
+// StreamAuthInterceptor authorizes streaming calls against the policy of their method.
+func StreamAuthInterceptor(policies Policies, keys APIKeyAuthenticator) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		newCtx, err := authorize(ss.Context(), policies, keys, info.FullMethod)
+		if err != nil {
+			return err
 		}
+		return handler(srv, &authorizedStream{ServerStream: ss, ctx: newCtx})
+	}
+}
+
+// authorizedStream hands the context carrying the claims to stream handlers.
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
 
-		tokenList := md["authorization"]
-		if len(tokenList) == 0 {
-			return nil, status.Errorf(codes.Unauthenticated, "authorization token is required")
+// authorize checks the caller against the policy of method and returns the
+// context the handler runs with. Unknown methods are denied.
+func authorize(ctx context.Context, policies Policies, keys APIKeyAuthenticator, method string) (context.Context, error) {
+	policy, ok := policies[method]
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "no authorization policy for %s", method)
+	}
+
+	claims, err := callerClaims(ctx, keys)
+	if policy.Public {
+		// public methods may still behave differently for a known caller
+		if err == nil {
+			ctx = ContextWithClaims(ctx, claims)
 		}
-		tokenStr := strings.TrimPrefix(tokenList[0], "Bearer ")
+		return ctx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		pubKey, err := jwt2.GetPublicKey()
+	if !policy.allows(claims) {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	if policy.needsStepUp(claims) {
+		return nil, stepUpRequired(policy)
+	}
+	return ContextWithClaims(ctx, claims), nil
+}
+
+// StepUpRequiredReason is the ErrorInfo reason of calls refused for an authentication that
+// is too old or too weak. The metadata tells the requirement, UserService.StepUp meets it.
+const StepUpRequiredReason = "STEP_UP_REQUIRED"
+
+func stepUpRequired(policy Policy) error {
+	info := &errdetails.ErrorInfo{
+		Reason:   StepUpRequiredReason,
+		Domain:   "user-service",
+		Metadata: map[string]string{},
+	}
+	if policy.MaxAuthAge > 0 {
+		info.Metadata["max_age"] = strconv.Itoa(int(policy.MaxAuthAge.Seconds()))
+	}
+	if len(policy.AuthMethods) > 0 {
+		info.Metadata["amr"] = strings.Join(policy.AuthMethods, " ")
+	}
+
+	st, err := status.New(codes.Unauthenticated, "a recent authentication is required, step up first").WithDetails(info)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "a recent authentication is required, step up first")
+	}
+	return st.Err()
+}
+
+func callerClaims(ctx context.Context, keys APIKeyAuthenticator) (*jwt2.JWTClaims, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "missing metadata")
+	}
+
+	if keyList := md["x-api-key"]; len(keyList) > 0 && keys != nil {
+		claims, err := keys.AuthenticateKey(keyList[0])
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to load public key: %v", err)
+			return nil, status.Errorf(codes.Unauthenticated, "invalid api key")
 		}
+		return claims, nil
+	}
 
-		claims, err := jwt.ParseWithClaims(tokenStr, &jwt2.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return pubKey, nil
-		})
-		if err != nil || !claims.Valid {
-			return nil, status.Errorf(codes.Unauthenticated, "invalid token")
-		}
+	tokenList := md["authorization"]
+	if len(tokenList) == 0 {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization token is required")
+	}
+	return accessClaims(tokenList[0])
+}
 
-		newCtx := context.WithValue(ctx, "user", claims)
-		return handler(newCtx, req)
+func accessClaims(token string) (*jwt2.JWTClaims, error) {
+	// the key is picked by kid and the algorithm is pinned to that key
+	claims, err := jwt2.ValidateAccessToken(token)
+	if err == jwt2.ErrTokenRevoked {
+		return nil, status.Errorf(codes.Unauthenticated, "token has been revoked")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
 	}
+	return claims, nil
 }
@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func initTestKeys(t *testing.T) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := jwt.Init(jwt.JWTConfig{
+		PrivateKey:      pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		PublicKey:       pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}),
+		AccessTokenExp:  1,
+		RefreshTokenExp: 1,
+		ClientTokenExp:  time.Minute,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func accessToken(t *testing.T, access ...string) string {
+	t.Helper()
+
+	tc := jwt.TokenConfig{ID: "1", Username: "user", Access: access}
+	token, _, err := tc.GenerateAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestAuthInterceptorPolicies(t *testing.T) {
+	policies := Policies{
+		"/test.Service/Public":        PublicMethod(),
+		"/test.Service/Authenticated": Authenticated(),
+		"/test.Service/Delete":        RequirePermissions("thing.delete"),
+		"/test.Service/Sessions":      Authenticated().WithSessionOnly(),
+	}
+	interceptor := AuthInterceptor(policies, nil)
+
+	initTestKeys(t)
+	reader := accessToken(t, "thing.read")
+	admin := accessToken(t, AnyPermission)
+
+	tc := jwt.ClientTokenConfig{ClientID: "service", Scopes: []string{"thing.delete"}}
+	service, _, err := tc.GenerateAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method string
+		token  string
+		want   codes.Code
+		claims bool
+	}{
+		{"/test.Service/Public", "", codes.OK, false},
+		{"/test.Service/Public", reader, codes.OK, true},
+		{"/test.Service/Authenticated", "", codes.Unauthenticated, false},
+		{"/test.Service/Authenticated", "garbage", codes.Unauthenticated, false},
+		{"/test.Service/Authenticated", reader, codes.OK, true},
+		{"/test.Service/Delete", reader, codes.PermissionDenied, false},
+		{"/test.Service/Delete", admin, codes.OK, true},
+		{"/test.Service/Unmapped", admin, codes.PermissionDenied, false},
+		{"/test.Service/Delete", service, codes.OK, true},
+		{"/test.Service/Authenticated", service, codes.PermissionDenied, false},
+		{"/test.Service/Sessions", reader, codes.OK, true},
+	}
+	for _, tt := range tests {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{})
+		if tt.token != "" {
+			ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+tt.token))
+		}
+
+		var gotClaims bool
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			_, gotClaims = ClaimsFromContext(ctx)
+			return nil, nil
+		}
+
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+		if got := status.Code(err); got != tt.want {
+			t.Errorf("%s: code = %v, want %v", tt.method, got, tt.want)
+		}
+		if gotClaims != tt.claims {
+			t.Errorf("%s: claims in context = %v, want %v", tt.method, gotClaims, tt.claims)
+		}
+	}
+}
+
+type fakeKeys map[string]*jwt.JWTClaims
+
+func (f fakeKeys) AuthenticateKey(key string) (*jwt.JWTClaims, error) {
+	claims, ok := f[key]
+	if !ok {
+		return nil, errors.New("invalid api key")
+	}
+	return claims, nil
+}
+
+func TestAuthInterceptorApiKey(t *testing.T) {
+	policies := Policies{
+		"/test.Service/Delete":   RequirePermissions("thing.delete"),
+		"/test.Service/Sessions": Authenticated().WithSessionOnly(),
+	}
+	keys := fakeKeys{
+		"uk_reader": {ID: "1", Access: []string{"thing.read"}, Principal: jwt.PrincipalAPIKey},
+		"uk_admin":  {ID: "1", Access: []string{"thing.delete"}, Principal: jwt.PrincipalAPIKey},
+	}
+	interceptor := AuthInterceptor(policies, keys)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	tests := []struct {
+		method string
+		key    string
+		want   codes.Code
+	}{
+		{"/test.Service/Delete", "uk_admin", codes.OK},
+		{"/test.Service/Delete", "uk_reader", codes.PermissionDenied},
+		{"/test.Service/Delete", "uk_unknown", codes.Unauthenticated},
+		{"/test.Service/Sessions", "uk_admin", codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", tt.key))
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+		if got := status.Code(err); got != tt.want {
+			t.Errorf("%s %s: code = %v, want %v", tt.method, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestAuthInterceptorStepUp(t *testing.T) {
+	policies := Policies{
+		"/test.Service/Recent":   Authenticated().WithMaxAuthAge(time.Minute),
+		"/test.Service/Passkey":  Authenticated().WithAuthMethods(jwt.AMRWebAuthn),
+		"/test.Service/Anything": Authenticated(),
+	}
+	interceptor := AuthInterceptor(policies, nil)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	initTestKeys(t)
+	tokenWith := func(auth jwt.Authentication) string {
+		tc := jwt.TokenConfig{ID: "1", Username: "user", Authentication: auth}
+		token, _, err := tc.GenerateAccessToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	old := jwt.NewAuthentication(jwt.AMRPassword)
+	old.AuthTime = time.Now().Add(-time.Hour).Unix()
+
+	fresh := tokenWith(jwt.NewAuthentication(jwt.AMRPassword))
+	stale := tokenWith(old)
+	passkey := tokenWith(jwt.NewAuthentication(jwt.AMRWebAuthn))
+	unknown := accessToken(t)
+
+	tests := []struct {
+		method string
+		token  string
+		want   codes.Code
+	}{
+		{"/test.Service/Recent", fresh, codes.OK},
+		{"/test.Service/Recent", stale, codes.Unauthenticated},
+		{"/test.Service/Recent", unknown, codes.Unauthenticated},
+		{"/test.Service/Passkey", passkey, codes.OK},
+		{"/test.Service/Passkey", fresh, codes.Unauthenticated},
+		{"/test.Service/Anything", stale, codes.OK},
+	}
+	for _, tt := range tests {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+tt.token))
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+		if got := status.Code(err); got != tt.want {
+			t.Errorf("%s: code = %v, want %v", tt.method, got, tt.want)
+		}
+		if tt.want == codes.Unauthenticated && !isStepUpRequired(err) {
+			t.Errorf("%s: error = %v, want the %s reason", tt.method, err, StepUpRequiredReason)
+		}
+	}
+}
+
+func isStepUpRequired(err error) bool {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Reason == StepUpRequiredReason {
+			return true
+		}
+	}
+	return false
+}
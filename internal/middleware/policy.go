@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"slices"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+)
+
+// AnyPermission in the Access claim satisfies every permission requirement.
+const AnyPermission = "*"
+
+type (
+	// Policy decides who may call a gRPC method.
+	Policy struct {
+		Public       bool     // no token required, a valid one is still put in the context
+		Permissions  []string // every one of them must be in the Access claim
+		AllowClients bool     // accept tokens of registered clients, otherwise only user tokens are
+		SessionOnly  bool     // accept only tokens of the user's own logins, not api keys or tokens delegated to an oauth client
+
+		// step-up requirements, a caller failing them is asked to authenticate again
+		MaxAuthAge  time.Duration // the user signed in or stepped up at most this long ago, 0 for any time
+		AuthMethods []string      // the user authenticated with one of these amr methods, any when empty
+	}
+
+	// Policies maps full method names, e.g. "/role.RoleService/DeleteRole", to their policy.
+	// Methods that are not in the map are denied.
+	Policies map[string]Policy
+)
+
+// PublicMethod may be called without a token.
+func PublicMethod() Policy {
+	return Policy{Public: true}
+}
+
+// Authenticated requires a valid user access token and nothing else.
+// It suits methods acting on the caller's own account, which a client does not have.
+func Authenticated() Policy {
+	return Policy{}
+}
+
+// RequirePermissions requires a valid access token granting all the given permissions,
+// issued to a user or to a client whose scopes include them.
+func RequirePermissions(permissions ...string) Policy {
+	return Policy{Permissions: permissions, AllowClients: true}
+}
+
+// WithMaxAuthAge additionally requires that the user authenticated within maxAge.
+func (p Policy) WithMaxAuthAge(maxAge time.Duration) Policy {
+	p.MaxAuthAge = maxAge
+	return p
+}
+
+// WithAuthMethods additionally requires that the user authenticated with one of methods, see jwt.AMRPassword.
+func (p Policy) WithAuthMethods(methods ...string) Policy {
+	p.AuthMethods = methods
+	return p
+}
+
+// WithSessionOnly additionally requires a token of a login session of the user.
+func (p Policy) WithSessionOnly() Policy {
+	p.SessionOnly = true
+	return p
+}
+
+// allows reports whether the caller satisfies the policy.
+func (p Policy) allows(claims *jwt.JWTClaims) bool {
+	if claims.IsClient() && !p.AllowClients {
+		return false
+	}
+	if p.SessionOnly && (claims.Principal == jwt.PrincipalAPIKey || claims.ClientID != "") {
+		return false
+	}
+	return HasPermissions(claims.Access, p.Permissions...)
+}
+
+// needsStepUp reports whether the caller, though allowed, has to authenticate again first.
+// Client tokens and api keys carry no authentication of a user and never satisfy a requirement.
+func (p Policy) needsStepUp(claims *jwt.JWTClaims) bool {
+	if p.MaxAuthAge > 0 && claims.Age() > p.MaxAuthAge {
+		return true
+	}
+	return len(p.AuthMethods) > 0 && !claims.UsedAny(p.AuthMethods...)
+}
+
+// HasPermissions reports whether access, the Access claim, grants every one of permissions.
+func HasPermissions(access []string, permissions ...string) bool {
+	if slices.Contains(access, AnyPermission) {
+		return true
+	}
+	for _, permission := range permissions {
+		if !slices.Contains(access, permission) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/apikey"
+	"github.com/yasinsaee/go-user-service/pkg/logger"
+	mongo2 "github.com/yasinsaee/go-user-service/pkg/mongo"
+	"github.com/yasinsaee/go-user-service/pkg/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mongoApiKeyRepository implements the ApiKeyRepository interface using MongoDB.
+type mongoApiKeyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoApiKeyRepository returns a new instance of mongoApiKeyRepository.
+func NewMongoApiKeyRepository(db *mongo.Database, collectionName string) apikey.ApiKeyRepository {
+	return &mongoApiKeyRepository{
+		collection: db.Collection(collectionName),
+	}
+}
+
+// Create inserts a new api key into the database and sets the creation timestamp.
+func (r *mongoApiKeyRepository) Create(k *apikey.ApiKey) error {
+	k.CreatedAt = time.Now().UTC()
+	k.UpdatedAt = time.Now().UTC()
+	return mongo2.Create(k)
+}
+
+// FindByID retrieves an api key by its ID (string or ObjectID).
+func (r *mongoApiKeyRepository) FindByID(id any) (*apikey.ApiKey, error) {
+	k := new(apikey.ApiKey)
+	err := mongo2.Get(r.collection.Name(), id, k)
+	return k, err
+}
+
+// FindByHash returns the api key with the given key hash.
+func (r *mongoApiKeyRepository) FindByHash(hash string) (*apikey.ApiKey, error) {
+	k := new(apikey.ApiKey)
+	err := mongo2.FindOne(r.collection.Name(), bson.M{"key_hash": hash}, k)
+	return k, err
+}
+
+// FindByUserID returns every api key of a user.
+func (r *mongoApiKeyRepository) FindByUserID(userID any) (apikey.ApiKeys, error) {
+	objID, err := util.ToObjectID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(apikey.ApiKeys, 0)
+	if err := mongo2.Find(r.collection.Name(), bson.M{"user_id": objID}, &keys); err != nil {
+		logger.Error("error while fetching api keys: ", err.Error())
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Update modifies an existing api key and sets the update timestamp.
+func (r *mongoApiKeyRepository) Update(k *apikey.ApiKey) error {
+	k.UpdatedAt = time.Now().UTC()
+	return mongo2.Update(k)
+}
+
+// Delete removes an api key by its ID after converting it to ObjectID.
+func (r *mongoApiKeyRepository) Delete(id any) error {
+	objID, err := util.ToObjectID(id)
+	if err != nil {
+		logger.Error("error while delete api key: ", err.Error())
+		return err
+	}
+	return mongo2.RemoveOne(r.collection.Name(), bson.M{"_id": objID})
+}
+
+// DeleteByUserID removes every api key of a user.
+func (r *mongoApiKeyRepository) DeleteByUserID(userID any) error {
+	objID, err := util.ToObjectID(userID)
+	if err != nil {
+		logger.Error("error while delete api keys: ", err.Error())
+		return err
+	}
+	return mongo2.RemoveMany(r.collection.Name(), bson.M{"user_id": objID})
+}
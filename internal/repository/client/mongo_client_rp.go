@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/client"
+	"github.com/yasinsaee/go-user-service/pkg/logger"
+	mongo2 "github.com/yasinsaee/go-user-service/pkg/mongo"
+	"github.com/yasinsaee/go-user-service/pkg/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mongoClientRepository implements the ClientRepository interface using MongoDB.
+type mongoClientRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoClientRepository returns a new instance of mongoClientRepository.
+func NewMongoClientRepository(db *mongo.Database, collectionName string) client.ClientRepository {
+	return &mongoClientRepository{
+		collection: db.Collection(collectionName),
+	}
+}
+
+// Create inserts a new client into the database and sets the creation timestamp.
+func (r *mongoClientRepository) Create(c *client.Client) error {
+	c.CreatedAt = time.Now().UTC()
+	c.UpdatedAt = time.Now().UTC()
+	return mongo2.Create(c)
+}
+
+// FindByID retrieves a client by its ID (string or ObjectID).
+func (r *mongoClientRepository) FindByID(id any) (*client.Client, error) {
+	c := new(client.Client)
+	err := mongo2.Get(r.collection.Name(), id, c)
+	return c, err
+}
+
+// FindByClientID returns the client registered with the given client_id.
+func (r *mongoClientRepository) FindByClientID(clientID string) (*client.Client, error) {
+	c := new(client.Client)
+	err := mongo2.FindOne(r.collection.Name(), bson.M{"client_id": clientID}, c)
+	return c, err
+}
+
+// Update modifies an existing client and sets the update timestamp.
+func (r *mongoClientRepository) Update(c *client.Client) error {
+	c.UpdatedAt = time.Now().UTC()
+	return mongo2.Update(c)
+}
+
+// Delete removes a client by its ID after converting it to ObjectID.
+func (r *mongoClientRepository) Delete(id any) error {
+	objID, err := util.ToObjectID(id)
+	if err != nil {
+		logger.Error("error while delete client: ", err.Error())
+		return err
+	}
+	return mongo2.RemoveOne(r.collection.Name(), bson.M{"_id": objID})
+}
+
+// List returns all clients from the collection.
+func (r *mongoClientRepository) List() (client.Clients, error) {
+	clients := make(client.Clients, 0)
+	err := mongo2.Find(r.collection.Name(), bson.M{}, &clients)
+	if err != nil {
+		logger.Error("error while fetching clients: ", err.Error())
+		return nil, err
+	}
+
+	return clients, nil
+}
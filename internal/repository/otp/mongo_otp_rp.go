@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"github.com/yasinsaee/go-user-service/internal/domain/otp"
@@ -71,6 +72,22 @@ func (r *mongoOTPRepository) FindByReceiverAndCodeAndType(receiver, code string,
 	return o, err
 }
 
+// MarkUsed marks the OTP as used unless it is already, of two requests with the same code
+// only one gets true.
+func (r *mongoOTPRepository) MarkUsed(id any) (bool, error) {
+	objID, err := util.ToObjectID(id)
+	if err != nil {
+		return false, err
+	}
+	filter := bson.M{"_id": objID, "used": false}
+	update := bson.M{"$set": bson.M{"used": true, "updated_at": time.Now().UTC()}}
+	res, err := r.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
 // Update updates an existing OTP record.
 func (r *mongoOTPRepository) Update(o *otp.Otp) error {
 	o.UpdatedAt = time.Now().UTC()
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+	"github.com/yasinsaee/go-user-service/pkg/logger"
+	mongo2 "github.com/yasinsaee/go-user-service/pkg/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mongoSigningKeyRepository implements the jwt.KeyStore interface using MongoDB.
+type mongoSigningKeyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoSigningKeyRepository returns a new instance of mongoSigningKeyRepository.
+func NewMongoSigningKeyRepository(db *mongo.Database, collectionName string) jwt.KeyStore {
+	return &mongoSigningKeyRepository{
+		collection: db.Collection(collectionName),
+	}
+}
+
+// List returns every signing key of the key ring.
+func (r *mongoSigningKeyRepository) List() ([]*jwt.SigningKey, error) {
+	keys := make([]*jwt.SigningKey, 0)
+	err := mongo2.Find(r.collection.Name(), bson.M{}, &keys)
+	if err != nil {
+		logger.Error("error while fetching signing keys: ", err.Error())
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Save inserts a new signing key or updates an existing one.
+func (r *mongoSigningKeyRepository) Save(key *jwt.SigningKey) error {
+	if key.ID.IsZero() {
+		return mongo2.Create(key)
+	}
+	return mongo2.Update(key)
+}
+
+// Delete removes a signing key by its kid.
+func (r *mongoSigningKeyRepository) Delete(kid string) error {
+	return mongo2.RemoveOne(r.collection.Name(), bson.M{"kid": kid})
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"github.com/yasinsaee/go-user-service/internal/domain/user"
@@ -75,3 +76,58 @@ func (r *mongoUserRepository) List() (user.Users, error) {
 
 	return users, nil
 }
+
+// FindByPhoneNumber returns the user with the given phone number, unlike FindByUsername
+// a username or email that looks like a phone number does not match.
+func (r *mongoUserRepository) FindByPhoneNumber(phoneNumber string) (*user.User, error) {
+	u := new(user.User)
+	err := mongo2.FindOne(r.collection.Name(), bson.M{"phone_number": phoneNumber}, u)
+	return u, err
+}
+
+// FindByExternalIdentity returns the user linked to the account of an upstream provider.
+func (r *mongoUserRepository) FindByExternalIdentity(provider, subject string) (*user.User, error) {
+	u := new(user.User)
+	query := bson.M{
+		"external_identities": bson.M{
+			"$elemMatch": bson.M{"provider": provider, "subject": subject},
+		},
+	}
+	err := mongo2.FindOne(r.collection.Name(), query, u)
+	return u, err
+}
+
+// ConsumeTOTPStep stores step as the last accepted one unless it or a later step is stored
+// already, the filter makes it safe against two requests with the same code.
+func (r *mongoUserRepository) ConsumeTOTPStep(id any, step int64) (bool, error) {
+	objID, err := util.ToObjectID(id)
+	if err != nil {
+		return false, err
+	}
+	filter := bson.M{"_id": objID, "mfa.last_step": bson.M{"$not": bson.M{"$gte": step}}}
+	update := bson.M{"$set": bson.M{"mfa.last_step": step, "updated_at": time.Now().UTC()}}
+	res, err := r.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+// ConsumeRecoveryCode pulls the hash out of the user's recovery codes, only one of two
+// requests with the same code removes it.
+func (r *mongoUserRepository) ConsumeRecoveryCode(id any, hash string) (bool, error) {
+	objID, err := util.ToObjectID(id)
+	if err != nil {
+		return false, err
+	}
+	filter := bson.M{"_id": objID, "mfa.recovery_codes": hash}
+	update := bson.M{
+		"$pull": bson.M{"mfa.recovery_codes": hash},
+		"$set":  bson.M{"updated_at": time.Now().UTC()},
+	}
+	res, err := r.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
@@ -0,0 +1,163 @@
+package apikey
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/apikey"
+	"github.com/yasinsaee/go-user-service/internal/domain/permission"
+	"github.com/yasinsaee/go-user-service/internal/domain/role"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+	"github.com/yasinsaee/go-user-service/pkg/logger"
+	"github.com/yasinsaee/go-user-service/pkg/util"
+)
+
+const (
+	keyPrefix = "uk_"
+
+	// last use is recorded at most this often, not on every request
+	lastUsedResolution = time.Minute
+)
+
+var ErrInvalidApiKey = errors.New("invalid api key")
+
+// apiKeyServiceImpl is the concrete implementation of ApiKeyService.
+type apiKeyServiceImpl struct {
+	repo     apikey.ApiKeyRepository
+	users    user.UserRepository
+	rService role.RoleService
+	pService permission.PermissionService
+}
+
+// NewApiKeyService creates a new instance of ApiKeyService.
+func NewApiKeyService(repo apikey.ApiKeyRepository, users user.UserRepository, rService role.RoleService, pService permission.PermissionService) apikey.ApiKeyService {
+	return &apiKeyServiceImpl{
+		repo:     repo,
+		users:    users,
+		rService: rService,
+		pService: pService,
+	}
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *apiKeyServiceImpl) Create(k *apikey.ApiKey) (string, error) {
+	if k.Name == "" {
+		return "", errors.New("api key name is required")
+	}
+	if !k.ExpiresAt.IsZero() && k.ExpiresAt.Before(time.Now().UTC()) {
+		return "", errors.New("api key expiry is in the past")
+	}
+
+	u, err := s.users.FindByID(k.UserID)
+	if err != nil || u == nil {
+		return "", errors.New("api key owner not found")
+	}
+	_, allowed := s.userAccess(u)
+	for _, p := range k.Permissions {
+		if !slices.Contains(allowed, p) && !slices.Contains(allowed, "*") {
+			return "", errors.New("api key permission " + p + " is not granted to the user")
+		}
+	}
+
+	// the key is random enough for a plain hash, unlike a password
+	token, err := util.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	key := keyPrefix + token
+	k.Prefix = key[:len(keyPrefix)+6]
+	k.KeyHash = hashKey(key)
+
+	if err := s.repo.Create(k); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func (s *apiKeyServiceImpl) GetByID(id any) (*apikey.ApiKey, error) {
+	return s.repo.FindByID(id)
+}
+
+func (s *apiKeyServiceImpl) ListByUser(userID any) (apikey.ApiKeys, error) {
+	return s.repo.FindByUserID(userID)
+}
+
+func (s *apiKeyServiceImpl) Delete(id any) error {
+	return s.repo.Delete(id)
+}
+
+func (s *apiKeyServiceImpl) AuthenticateKey(key string) (*jwt.JWTClaims, error) {
+	k, err := s.repo.FindByHash(hashKey(key))
+	if err != nil || k == nil {
+		return nil, ErrInvalidApiKey
+	}
+
+	now := time.Now().UTC()
+	if k.IsExpired(now) {
+		return nil, ErrInvalidApiKey
+	}
+
+	u, err := s.users.FindByID(k.UserID)
+	if err != nil || u == nil || !u.Active() {
+		return nil, ErrInvalidApiKey
+	}
+
+	// a permission taken from the owner is taken from the key as well
+	roles, granted := s.userAccess(u)
+	access := make([]string, 0, len(k.Permissions))
+	for _, p := range k.Permissions {
+		if slices.Contains(granted, p) || slices.Contains(granted, "*") {
+			access = append(access, p)
+		}
+	}
+
+	if now.Sub(k.LastUsedAt) > lastUsedResolution {
+		k.LastUsedAt = now
+		if err := s.repo.Update(k); err != nil {
+			logger.Error("failed to record api key use: ", err.Error())
+		}
+	}
+
+	claims := &jwt.JWTClaims{
+		ID:        u.ID.Hex(),
+		Username:  u.Username,
+		Roles:     roles,
+		Access:    access,
+		Type:      jwt.TokenTypeAccess,
+		Principal: jwt.PrincipalAPIKey,
+	}
+	claims.Subject = u.ID.Hex()
+	claims.TokenID = k.ID.Hex()
+	claims.IssuedAt = k.CreatedAt.Unix()
+	if !k.ExpiresAt.IsZero() {
+		claims.ExpiresAt = k.ExpiresAt.Unix()
+	}
+	return claims, nil
+}
+
+// userAccess returns the role and permission names the user currently has.
+func (s *apiKeyServiceImpl) userAccess(u *user.User) (roles []string, permissions []string) {
+	for _, roleID := range u.Roles {
+		r, err := s.rService.GetByID(roleID)
+		if err != nil {
+			continue
+		}
+		roles = append(roles, r.Name)
+		for _, permissionID := range r.Permissions {
+			p, err := s.pService.GetByID(permissionID)
+			if err != nil {
+				continue
+			}
+			permissions = append(permissions, p.Name)
+		}
+	}
+	return
+}
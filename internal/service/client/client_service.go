@@ -0,0 +1,101 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/client"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/pkg/util"
+)
+
+// clientServiceImpl is the concrete implementation of ClientService.
+type clientServiceImpl struct {
+	repo   client.ClientRepository
+	hasher user.PasswordHasher
+}
+
+// NewClientService creates a new instance of ClientService. Secrets are hashed like user passwords.
+func NewClientService(repo client.ClientRepository, hasher user.PasswordHasher) client.ClientService {
+	return &clientServiceImpl{
+		repo:   repo,
+		hasher: hasher,
+	}
+}
+
+func (s *clientServiceImpl) Register(c *client.Client) (string, error) {
+	if c.Name == "" {
+		return "", errors.New("client name is required")
+	}
+	if err := client.ValidateRedirectURIs(c.RedirectURIs); err != nil {
+		return "", err
+	}
+
+	clientID, err := util.RandomToken(16)
+	if err != nil {
+		return "", err
+	}
+	secret, err := util.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	c.ClientID = clientID
+	hash, err := s.hasher.Hash(secret)
+	if err != nil {
+		return "", err
+	}
+	c.SecretHash = hash
+	c.IsActive = true
+
+	if err := s.repo.Create(c); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+func (s *clientServiceImpl) GetByID(id any) (*client.Client, error) {
+	return s.repo.FindByID(id)
+}
+
+func (s *clientServiceImpl) GetByClientID(clientID string) (*client.Client, error) {
+	return s.repo.FindByClientID(clientID)
+}
+
+func (s *clientServiceImpl) Update(c *client.Client) error {
+	if err := client.ValidateRedirectURIs(c.RedirectURIs); err != nil {
+		return err
+	}
+	return s.repo.Update(c)
+}
+
+func (s *clientServiceImpl) Delete(id any) error {
+	return s.repo.Delete(id)
+}
+
+func (s *clientServiceImpl) ListAll() (client.Clients, error) {
+	return s.repo.List()
+}
+
+func (s *clientServiceImpl) Authenticate(clientID, secret string) (*client.Client, error) {
+	c, err := s.repo.FindByClientID(clientID)
+	if err != nil || c == nil {
+		return nil, errors.New("invalid client credentials")
+	}
+
+	if !c.IsActive {
+		return nil, errors.New("invalid client credentials")
+	}
+	ok, rehash := s.hasher.Verify(secret, c.SecretHash)
+	if !ok {
+		return nil, errors.New("invalid client credentials")
+	}
+
+	// secrets of earlier releases are bcrypt hashes at cost 8, failing to replace one is not fatal
+	if rehash {
+		if hash, err := s.hasher.Hash(secret); err == nil {
+			c.SecretHash = hash
+			_ = s.repo.Update(c)
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,189 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/federation"
+	"github.com/yasinsaee/go-user-service/internal/domain/federation/config"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/pkg/oidc"
+	"github.com/yasinsaee/go-user-service/pkg/util"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// stateTTL is how long the user has to sign in at the provider
+const stateTTL = 10 * time.Minute
+
+type provider struct {
+	*oidc.Provider
+	autoRegister bool
+}
+
+// federationServiceImpl is the concrete implementation of FederationService.
+type federationServiceImpl struct {
+	providers map[string]provider
+	names     []string
+	store     federation.LoginStateStore
+	users     user.UserRepository
+	hasher    user.PasswordHasher
+}
+
+// NewFederationService creates a new instance of FederationService.
+func NewFederationService(providers []config.ProviderConfig, store federation.LoginStateStore, users user.UserRepository, hasher user.PasswordHasher) federation.FederationService {
+	s := &federationServiceImpl{
+		providers: make(map[string]provider, len(providers)),
+		store:     store,
+		users:     users,
+		hasher:    hasher,
+	}
+	for _, p := range providers {
+		s.providers[p.Name] = provider{Provider: oidc.NewProvider(p.Config), autoRegister: p.AutoRegister}
+		s.names = append(s.names, p.Name)
+	}
+	return s
+}
+
+func (s *federationServiceImpl) Providers() []string {
+	return s.names
+}
+
+func (s *federationServiceImpl) AuthURL(ctx context.Context, name string, state *federation.LoginState) (string, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return "", federation.ErrUnknownProvider
+	}
+
+	var err error
+	if state.ID, err = util.RandomToken(32); err != nil {
+		return "", err
+	}
+	state.Provider = name
+	if state.CodeVerifier, err = util.RandomToken(32); err != nil {
+		return "", err
+	}
+	if state.Nonce, err = util.RandomToken(16); err != nil {
+		return "", err
+	}
+	state.ExpiresAt = time.Now().UTC().Add(stateTTL)
+
+	authURL, err := p.AuthCodeURL(ctx, state.RedirectURI, state.ID, state.Nonce, state.CodeVerifier)
+	if err != nil {
+		return "", err
+	}
+	if err := s.store.Save(state); err != nil {
+		return "", err
+	}
+	return authURL, nil
+}
+
+func (s *federationServiceImpl) Callback(ctx context.Context, stateID, code string) (*user.User, *federation.LoginState, error) {
+	if stateID == "" {
+		return nil, nil, federation.ErrInvalidState
+	}
+	state, err := s.store.Take(stateID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if state == nil || time.Now().UTC().After(state.ExpiresAt) {
+		return nil, nil, federation.ErrInvalidState
+	}
+
+	p, ok := s.providers[state.Provider]
+	if !ok {
+		return nil, state, federation.ErrUnknownProvider
+	}
+	// the provider sends an error instead of a code when the user cancelled
+	if code == "" {
+		return nil, state, federation.ErrLoginDenied
+	}
+
+	claims, err := p.Exchange(ctx, code, state.RedirectURI, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		return nil, state, err
+	}
+
+	u, err := s.resolveUser(p, claims)
+	if err != nil {
+		return nil, state, err
+	}
+	return u, state, nil
+}
+
+// resolveUser returns the user linked to the identity, linking an account with the same
+// verified email on first login or provisioning a new one when the provider allows it.
+func (s *federationServiceImpl) resolveUser(p provider, claims *oidc.Claims) (*user.User, error) {
+	now := time.Now().UTC()
+
+	u, err := s.users.FindByExternalIdentity(p.Name(), claims.Subject)
+	if err == nil {
+		if !u.Active() {
+			return nil, user.ErrDeactivated
+		}
+		u.LastLogin = now
+		return u, s.users.Update(u)
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	// an unverified email could belong to anyone, it must not take over an account
+	if claims.Email == "" || !claims.IsEmailVerified() {
+		return nil, federation.ErrEmailNotVerified
+	}
+	identity := user.ExternalIdentity{
+		Provider: p.Name(),
+		Subject:  claims.Subject,
+		LinkedAt: now,
+	}
+
+	u, err = s.users.FindByUsername(claims.Email)
+	switch {
+	case err == nil && strings.EqualFold(u.Email, claims.Email):
+		if !u.Active() {
+			return nil, user.ErrDeactivated
+		}
+		u.ExternalIdentities = append(u.ExternalIdentities, identity)
+		u.LastLogin = now
+		return u, s.users.Update(u)
+	case err == nil:
+		// the address is the username or phone of another account
+		return nil, federation.ErrNoAccount
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return nil, err
+	}
+
+	if !p.autoRegister {
+		return nil, federation.ErrNoAccount
+	}
+	// nobody knows it, the account signs in through the provider until a password is reset
+	password, err := util.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	if password, err = s.hasher.Hash(password); err != nil {
+		return nil, err
+	}
+	u = &user.User{
+		FirstName:          claims.GivenName,
+		LastName:           claims.FamilyName,
+		Username:           claims.Email,
+		Email:              claims.Email,
+		ProfileImage:       claims.Picture,
+		Password:           password,
+		IsActive:           true,
+		ExternalIdentities: []user.ExternalIdentity{identity},
+		LastLogin:          now,
+		CreatedAt:          now,
+		PasswordChangedAt:  now, // only sign-ins with the password check its age
+	}
+	if u.FirstName == "" && u.LastName == "" {
+		u.FirstName = claims.Name
+	}
+	if err := s.users.Create(u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
@@ -0,0 +1,46 @@
+package login_state_store
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/federation"
+	"github.com/yasinsaee/go-user-service/pkg/redis"
+)
+
+type loginStateStoreImpl struct{}
+
+// NewLoginStateStore returns a new instance of LoginStateStore.
+func NewLoginStateStore() *loginStateStoreImpl {
+	return &loginStateStoreImpl{}
+}
+
+func (s *loginStateStoreImpl) Save(state *federation.LoginState) error {
+	ttl := time.Until(state.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return redis.Set("federated_login:"+state.ID, data, ttl)
+}
+
+func (s *loginStateStoreImpl) Take(id string) (*federation.LoginState, error) {
+	val, err := redis.GetDel("federated_login:" + id)
+	if err == redis.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state := &federation.LoginState{}
+	if err := json.Unmarshal([]byte(val), state); err != nil {
+		return nil, err
+	}
+	state.ID = id
+	return state, nil
+}
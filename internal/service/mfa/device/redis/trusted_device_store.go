@@ -0,0 +1,99 @@
+package trusted_device_store
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/mfa"
+	"github.com/yasinsaee/go-user-service/pkg/redis"
+)
+
+type trustedDeviceStoreImpl struct{}
+
+// NewTrustedDeviceStore returns a new instance of TrustedDeviceStore.
+func NewTrustedDeviceStore() *trustedDeviceStoreImpl {
+	return &trustedDeviceStoreImpl{}
+}
+
+func (s *trustedDeviceStoreImpl) Save(device *mfa.TrustedDevice) error {
+	ttl := time.Until(device.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(device)
+	if err != nil {
+		return err
+	}
+	if err := redis.Set("trusted_device:"+device.UserID+":"+device.ID, data, ttl); err != nil {
+		return err
+	}
+
+	// every device shares the ttl, so the index outlives the devices already in it
+	index := "trusted_devices:" + device.UserID
+	if err := redis.SAdd(index, device.ID); err != nil {
+		return err
+	}
+	return redis.Expire(index, ttl)
+}
+
+func (s *trustedDeviceStoreImpl) Get(userID, deviceID string) (*mfa.TrustedDevice, error) {
+	data, err := redis.Get("trusted_device:" + userID + ":" + deviceID)
+	if err == redis.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	device := new(mfa.TrustedDevice)
+	if err := json.Unmarshal([]byte(data), device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+func (s *trustedDeviceStoreImpl) List(userID string) (mfa.TrustedDevices, error) {
+	index := "trusted_devices:" + userID
+	ids, err := redis.SMembers(index)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make(mfa.TrustedDevices, 0, len(ids))
+	for _, id := range ids {
+		device, err := s.Get(userID, id)
+		if err != nil {
+			return nil, err
+		}
+		if device == nil {
+			if err := redis.SRem(index, id); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		devices = append(devices, *device)
+	}
+	return devices, nil
+}
+
+func (s *trustedDeviceStoreImpl) Delete(userID, deviceID string) error {
+	if err := redis.Remove("trusted_device:" + userID + ":" + deviceID); err != nil {
+		return err
+	}
+	return redis.SRem("trusted_devices:"+userID, deviceID)
+}
+
+func (s *trustedDeviceStoreImpl) DeleteAll(userID string) error {
+	index := "trusted_devices:" + userID
+	ids, err := redis.SMembers(index)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := redis.Remove("trusted_device:" + userID + ":" + id); err != nil {
+			return err
+		}
+	}
+	return redis.Remove(index)
+}
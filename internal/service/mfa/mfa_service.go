@@ -0,0 +1,278 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/mfa"
+	"github.com/yasinsaee/go-user-service/internal/domain/mfa/config"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+	"github.com/yasinsaee/go-user-service/pkg/logger"
+	"github.com/yasinsaee/go-user-service/pkg/totp"
+	"github.com/yasinsaee/go-user-service/pkg/util"
+)
+
+const (
+	recoveryCodeCount = 10
+
+	// failed codes allowed per user before it is locked out for attemptWindow
+	maxAttempts   = 5
+	attemptWindow = 5 * time.Minute
+)
+
+// mfaServiceImpl is the concrete implementation of MFAService.
+type mfaServiceImpl struct {
+	users    user.UserRepository
+	attempts mfa.AttemptStore
+	devices  mfa.TrustedDeviceStore
+	config   config.MFAConfig
+}
+
+// NewMFAService creates a new instance of MFAService.
+func NewMFAService(users user.UserRepository, attempts mfa.AttemptStore, devices mfa.TrustedDeviceStore, config config.MFAConfig) mfa.MFAService {
+	return &mfaServiceImpl{
+		users:    users,
+		attempts: attempts,
+		devices:  devices,
+		config:   config,
+	}
+}
+
+// hashRecoveryCode normalizes the code as users type it, dashes and case do not matter.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// newRecoveryCodes returns codes like "k3m9x-q2v7p" and their hashes, 50 random bits each.
+func newRecoveryCodes() ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(encoding.EncodeToString(b))[:10]
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = hashRecoveryCode(code)
+	}
+	return codes, hashes, nil
+}
+
+func (s *mfaServiceImpl) EnrollTOTP(u *user.User) (string, string, error) {
+	if u.MFA.Enabled {
+		return "", "", mfa.ErrAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+	u.MFA.PendingSecret = secret
+	if err := s.users.Update(u); err != nil {
+		return "", "", err
+	}
+
+	account := u.Username
+	if account == "" {
+		account = u.Email
+	}
+	return secret, totp.URI(s.config.Issuer, account, secret), nil
+}
+
+func (s *mfaServiceImpl) ConfirmTOTP(u *user.User, code string) ([]string, error) {
+	if u.MFA.Enabled {
+		return nil, mfa.ErrAlreadyEnabled
+	}
+	if u.MFA.PendingSecret == "" {
+		return nil, mfa.ErrNoPendingEnrollment
+	}
+	if err := s.checkAttempts(u); err != nil {
+		return nil, err
+	}
+
+	step, ok := totp.Validate(u.MFA.PendingSecret, code, time.Now().UTC())
+	if !ok {
+		return nil, mfa.ErrInvalidCode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	u.MFA = user.MFA{
+		Enabled:       true,
+		Secret:        u.MFA.PendingSecret,
+		LastStep:      step,
+		RecoveryCodes: hashes,
+		EnabledAt:     time.Now().UTC(),
+	}
+	if err := s.users.Update(u); err != nil {
+		return nil, err
+	}
+	s.succeed(u)
+	return codes, nil
+}
+
+func (s *mfaServiceImpl) Verify(u *user.User, code string) error {
+	if !u.MFA.Enabled {
+		return mfa.ErrNotEnrolled
+	}
+	if err := s.checkAttempts(u); err != nil {
+		return err
+	}
+
+	// a code of an already accepted step is a replay, the conditional update also stops two
+	// requests with the same code
+	if step, ok := totp.Validate(u.MFA.Secret, code, time.Now().UTC()); ok {
+		consumed, err := s.users.ConsumeTOTPStep(u.ID, step)
+		if err != nil {
+			return err
+		}
+		if consumed {
+			u.MFA.LastStep = step
+			s.succeed(u)
+			return nil
+		}
+	}
+
+	hash := hashRecoveryCode(code)
+	if i := slices.Index(u.MFA.RecoveryCodes, hash); i >= 0 {
+		consumed, err := s.users.ConsumeRecoveryCode(u.ID, hash)
+		if err != nil {
+			return err
+		}
+		if consumed {
+			u.MFA.RecoveryCodes = slices.Delete(u.MFA.RecoveryCodes, i, i+1)
+			s.succeed(u)
+			return nil
+		}
+	}
+
+	return mfa.ErrInvalidCode
+}
+
+func (s *mfaServiceImpl) Disable(u *user.User, code string) error {
+	if err := s.Verify(u, code); err != nil {
+		return err
+	}
+
+	u.MFA = user.MFA{}
+	if err := s.users.Update(u); err != nil {
+		return err
+	}
+
+	// devices trusted for the old factor must not skip a factor enabled later
+	return s.devices.DeleteAll(u.ID.Hex())
+}
+
+func (s *mfaServiceImpl) RegenerateRecoveryCodes(u *user.User, code string) ([]string, error) {
+	if err := s.Verify(u, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	u.MFA.RecoveryCodes = hashes
+	if err := s.users.Update(u); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *mfaServiceImpl) TrustDevice(u *user.User, device *mfa.TrustedDevice) (string, error) {
+	if s.config.TrustedDeviceTTL <= 0 {
+		return "", mfa.ErrTrustDisabled
+	}
+
+	id, err := util.RandomToken(16)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now().UTC()
+	device.ID = id
+	device.UserID = u.ID.Hex()
+	device.CreatedAt = now
+	device.ExpiresAt = now.Add(s.config.TrustedDeviceTTL)
+
+	token, _, err := jwt.GenerateDeviceToken(device.UserID, device.ID, device.ExpiresAt)
+	if err != nil {
+		return "", err
+	}
+	if err := s.devices.Save(device); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *mfaServiceImpl) IsTrustedDevice(u *user.User, token string) bool {
+	if token == "" || s.config.TrustedDeviceTTL <= 0 {
+		return false
+	}
+
+	claims, err := jwt.ValidateDeviceToken(token)
+	if err != nil || claims.ID != u.ID.Hex() {
+		return false
+	}
+
+	device, err := s.devices.Get(claims.ID, claims.TokenID)
+	if err != nil {
+		logger.Error("trusted device not checked: ", err.Error())
+		return false
+	}
+	return device != nil
+}
+
+func (s *mfaServiceImpl) ListTrustedDevices(u *user.User) (mfa.TrustedDevices, error) {
+	return s.devices.List(u.ID.Hex())
+}
+
+func (s *mfaServiceImpl) RevokeTrustedDevice(u *user.User, id string) error {
+	if id == "" {
+		return s.devices.DeleteAll(u.ID.Hex())
+	}
+
+	device, err := s.devices.Get(u.ID.Hex(), id)
+	if err != nil {
+		return err
+	}
+	if device == nil {
+		return mfa.ErrDeviceNotFound
+	}
+	return s.devices.Delete(u.ID.Hex(), id)
+}
+
+func (s *mfaServiceImpl) TrustedDeviceTTL() time.Duration {
+	return s.config.TrustedDeviceTTL
+}
+
+// checkAttempts counts the attempt before its code is checked, so parallel guesses cannot
+// all pass a check of the failures so far. It fails closed, codes are not checked when the
+// attempts cannot be counted.
+func (s *mfaServiceImpl) checkAttempts(u *user.User) error {
+	attempts, err := s.attempts.Attempt(u.ID.Hex(), attemptWindow)
+	if err != nil {
+		return err
+	}
+	if attempts > maxAttempts {
+		return mfa.ErrTooManyAttempts
+	}
+	return nil
+}
+
+func (s *mfaServiceImpl) succeed(u *user.User) {
+	if err := s.attempts.Reset(u.ID.Hex()); err != nil {
+		logger.Error("mfa attempts not reset: ", err.Error())
+	}
+}
@@ -0,0 +1,226 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/yasinsaee/go-user-service/internal/domain/mfa"
+	"github.com/yasinsaee/go-user-service/internal/domain/mfa/config"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	trusted_device_store "github.com/yasinsaee/go-user-service/internal/service/mfa/device/redis"
+	mfa_attempt_store "github.com/yasinsaee/go-user-service/internal/service/mfa/redis"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+	"github.com/yasinsaee/go-user-service/pkg/redis"
+	"github.com/yasinsaee/go-user-service/pkg/totp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newTrustService returns the service with device tokens signed by a fresh key and the
+// devices kept in a throwaway Redis.
+func newTrustService(t *testing.T, ttl time.Duration) (*mfaServiceImpl, *miniredis.Miniredis) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := jwt.Init(jwt.JWTConfig{
+		PrivateKey:      pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		PublicKey:       pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}),
+		AccessTokenExp:  1,
+		RefreshTokenExp: 1,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	mr := miniredis.RunT(t)
+	redis.Init(redis.Config{Addr: mr.Addr()})
+	s := &mfaServiceImpl{
+		devices: trusted_device_store.NewTrustedDeviceStore(),
+		config:  config.MFAConfig{TrustedDeviceTTL: ttl},
+	}
+	return s, mr
+}
+
+func TestTrustedDeviceSkipsSecondFactor(t *testing.T) {
+	s, _ := newTrustService(t, time.Hour)
+	owner := &user.User{ID: primitive.NewObjectID()}
+	other := &user.User{ID: primitive.NewObjectID()}
+
+	token, err := s.TrustDevice(owner, &mfa.TrustedDevice{DeviceName: "laptop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsTrustedDevice(owner, token) {
+		t.Fatal("expected the device to be trusted")
+	}
+	if s.IsTrustedDevice(other, token) {
+		t.Fatal("expected the token not to skip the second factor of another user")
+	}
+	if s.IsTrustedDevice(owner, token+"x") || s.IsTrustedDevice(owner, "") {
+		t.Fatal("expected a broken token not to be trusted")
+	}
+
+	devices, err := s.ListTrustedDevices(owner)
+	if err != nil || len(devices) != 1 {
+		t.Fatalf("expected one trusted device, got %+v, %v", devices, err)
+	}
+	if err := s.RevokeTrustedDevice(owner, devices[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if s.IsTrustedDevice(owner, token) {
+		t.Fatal("expected a revoked device to ask for the second factor again")
+	}
+	if err := s.RevokeTrustedDevice(owner, devices[0].ID); !errors.Is(err, mfa.ErrDeviceNotFound) {
+		t.Fatalf("expected ErrDeviceNotFound, got %v", err)
+	}
+}
+
+func TestTrustedDeviceExpires(t *testing.T) {
+	s, mr := newTrustService(t, time.Hour)
+	u := &user.User{ID: primitive.NewObjectID()}
+
+	token, err := s.TrustDevice(u, &mfa.TrustedDevice{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr.FastForward(time.Hour)
+
+	if s.IsTrustedDevice(u, token) {
+		t.Fatal("expected the device to ask for the second factor again once its trust expired")
+	}
+	if devices, _ := s.ListTrustedDevices(u); len(devices) != 0 {
+		t.Fatalf("expected the expired device not to be listed, got %+v", devices)
+	}
+}
+
+func TestTrustDisabled(t *testing.T) {
+	s, _ := newTrustService(t, time.Hour)
+	u := &user.User{ID: primitive.NewObjectID()}
+	token, err := s.TrustDevice(u, &mfa.TrustedDevice{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// turning the feature off ends the trust of devices already trusted
+	s.config.TrustedDeviceTTL = 0
+	if s.IsTrustedDevice(u, token) {
+		t.Fatal("expected no device to be trusted while trust is disabled")
+	}
+	if _, err := s.TrustDevice(u, &mfa.TrustedDevice{}); !errors.Is(err, mfa.ErrTrustDisabled) {
+		t.Fatalf("expected ErrTrustDisabled, got %v", err)
+	}
+}
+
+// storedMFA keeps the second factor of one user as the database has it, apart from the
+// copies the requests loaded.
+type storedMFA struct {
+	user.UserRepository
+	mfa user.MFA
+}
+
+func (r *storedMFA) ConsumeTOTPStep(id any, step int64) (bool, error) {
+	if r.mfa.LastStep >= step {
+		return false, nil
+	}
+	r.mfa.LastStep = step
+	return true, nil
+}
+
+func (r *storedMFA) ConsumeRecoveryCode(id any, hash string) (bool, error) {
+	i := slices.Index(r.mfa.RecoveryCodes, hash)
+	if i < 0 {
+		return false, nil
+	}
+	r.mfa.RecoveryCodes = slices.Delete(r.mfa.RecoveryCodes, i, i+1)
+	return true, nil
+}
+
+// loaded returns the user as a request loads it, with a copy of the stored second factor.
+func (r *storedMFA) loaded(id primitive.ObjectID) *user.User {
+	m := r.mfa
+	m.RecoveryCodes = slices.Clone(r.mfa.RecoveryCodes)
+	return &user.User{ID: id, MFA: m}
+}
+
+// newVerifyService returns the service, a user with TOTP enabled and its recovery codes.
+func newVerifyService(t *testing.T) (*mfaServiceImpl, *storedMFA, *miniredis.Miniredis, []string) {
+	t.Helper()
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := &storedMFA{mfa: user.MFA{Enabled: true, Secret: secret, RecoveryCodes: hashes}}
+
+	mr := miniredis.RunT(t)
+	redis.Init(redis.Config{Addr: mr.Addr()})
+	s := &mfaServiceImpl{
+		users:    users,
+		attempts: mfa_attempt_store.NewAttemptStore(),
+	}
+	return s, users, mr, codes
+}
+
+func TestCodesAreSingleUse(t *testing.T) {
+	s, users, _, codes := newVerifyService(t)
+	id := primitive.NewObjectID()
+
+	// both requests loaded the user before either of them checked the code
+	first, second := users.loaded(id), users.loaded(id)
+	code, err := totp.Code(users.mfa.Secret, totp.Step(time.Now().UTC()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Verify(first, code); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Verify(second, code); !errors.Is(err, mfa.ErrInvalidCode) {
+		t.Fatalf("expected a replayed code to fail, got %v", err)
+	}
+
+	first, second = users.loaded(id), users.loaded(id)
+	if err := s.Verify(first, codes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Verify(second, codes[0]); !errors.Is(err, mfa.ErrInvalidCode) {
+		t.Fatalf("expected a used recovery code to fail, got %v", err)
+	}
+	if len(users.mfa.RecoveryCodes) != len(codes)-1 {
+		t.Fatalf("expected one recovery code to be used, %d left", len(users.mfa.RecoveryCodes))
+	}
+}
+
+func TestAttemptsAreCountedBeforeCodesAreChecked(t *testing.T) {
+	s, users, mr, codes := newVerifyService(t)
+	u := users.loaded(primitive.NewObjectID())
+
+	for i := 0; i < maxAttempts; i++ {
+		if err := s.Verify(u, "wrong-code"); !errors.Is(err, mfa.ErrInvalidCode) {
+			t.Fatalf("attempt %d: expected ErrInvalidCode, got %v", i+1, err)
+		}
+	}
+	if err := s.Verify(u, codes[0]); !errors.Is(err, mfa.ErrTooManyAttempts) {
+		t.Fatalf("expected even a right code to be refused once the attempts are used up, got %v", err)
+	}
+
+	mr.FastForward(attemptWindow)
+	if err := s.Verify(u, codes[0]); err != nil {
+		t.Fatalf("expected the code to pass once the window is over, got %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package mfa_attempt_store
+
+import (
+	"time"
+
+	"github.com/yasinsaee/go-user-service/pkg/redis"
+)
+
+// attemptScript counts the attempt and restarts its window at once, a counter without
+// expiry would lock the user out for good. KEYS is the counter, ARGV the window in
+// milliseconds.
+var attemptScript = redis.NewScript(`
+local attempts = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[1])
+return attempts
+`)
+
+type attemptStoreImpl struct{}
+
+// NewAttemptStore returns a new instance of AttemptStore.
+func NewAttemptStore() *attemptStoreImpl {
+	return &attemptStoreImpl{}
+}
+
+func (s *attemptStoreImpl) Attempt(userID string, window time.Duration) (int, error) {
+	attempts, err := redis.RunScript(attemptScript, []string{"mfa_attempts:" + userID}, window.Milliseconds())
+	if err != nil {
+		return 0, err
+	}
+	return int(attempts.(int64)), nil
+}
+
+func (s *attemptStoreImpl) Reset(userID string) error {
+	return redis.Remove("mfa_attempts:" + userID)
+}
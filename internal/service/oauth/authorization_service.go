@@ -0,0 +1,61 @@
+package oauth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/oauth"
+	"github.com/yasinsaee/go-user-service/pkg/util"
+)
+
+// codeTTL is how long a client has to redeem a code, RFC 6749 recommends at most 10 minutes
+const codeTTL = time.Minute
+
+// authorizationServiceImpl is the concrete implementation of AuthorizationService.
+type authorizationServiceImpl struct {
+	store oauth.AuthorizationCodeStore
+}
+
+// NewAuthorizationService creates a new instance of AuthorizationService.
+func NewAuthorizationService(store oauth.AuthorizationCodeStore) oauth.AuthorizationService {
+	return &authorizationServiceImpl{
+		store: store,
+	}
+}
+
+func (s *authorizationServiceImpl) IssueCode(c *oauth.AuthorizationCode) error {
+	if c.CodeChallengeMethod != oauth.CodeChallengeS256 || c.CodeChallenge == "" {
+		return errors.New("a S256 code challenge is required")
+	}
+
+	code, err := util.RandomToken(32)
+	if err != nil {
+		return err
+	}
+	c.Code = code
+	c.ExpiresAt = time.Now().UTC().Add(codeTTL)
+	return s.store.Save(c)
+}
+
+func (s *authorizationServiceImpl) RedeemCode(code, clientID, redirectURI, verifier string) (*oauth.AuthorizationCode, error) {
+	if code == "" {
+		return nil, oauth.ErrInvalidGrant
+	}
+
+	// the code is gone after the first attempt, whatever its outcome
+	c, err := s.store.Take(code)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil || time.Now().UTC().After(c.ExpiresAt) {
+		return nil, oauth.ErrInvalidGrant
+	}
+
+	if c.ClientID != clientID || c.RedirectURI != redirectURI {
+		return nil, oauth.ErrInvalidGrant
+	}
+	if !c.VerifyChallenge(verifier) {
+		return nil, oauth.ErrInvalidGrant
+	}
+	return c, nil
+}
@@ -0,0 +1,46 @@
+package authorization_code_store
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/oauth"
+	"github.com/yasinsaee/go-user-service/pkg/redis"
+)
+
+type authorizationCodeStoreImpl struct{}
+
+// NewAuthorizationCodeStore returns a new instance of AuthorizationCodeStore.
+func NewAuthorizationCodeStore() *authorizationCodeStoreImpl {
+	return &authorizationCodeStoreImpl{}
+}
+
+func (s *authorizationCodeStoreImpl) Save(code *oauth.AuthorizationCode) error {
+	ttl := time.Until(code.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(code)
+	if err != nil {
+		return err
+	}
+	return redis.Set("auth_code:"+code.Code, data, ttl)
+}
+
+func (s *authorizationCodeStoreImpl) Take(code string) (*oauth.AuthorizationCode, error) {
+	val, err := redis.GetDel("auth_code:" + code)
+	if err == redis.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &oauth.AuthorizationCode{}
+	if err := json.Unmarshal([]byte(val), c); err != nil {
+		return nil, err
+	}
+	c.Code = code
+	return c, nil
+}
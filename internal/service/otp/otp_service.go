@@ -104,12 +104,18 @@ func (s *OTPServiceImpl) SaveCode(receiver string, otpType otp.OtpType, code str
 }
 
 // ValidateCode checks whether code is valid for a given type, not expired, and not used.
+// Attempts are counted per receiver before the code is looked up so codes cannot be guessed.
 func (s *OTPServiceImpl) ValidateCode(receiver string, otpType otp.OtpType, code string) (bool, error) {
-	record, err := s.repo.FindByReceiverAndCodeAndType(receiver, code, otpType)
+	ok, err := s.AttemptVerify(receiver)
 	if err != nil {
 		return false, err
 	}
-	if record == nil {
+	if !ok {
+		return false, errors.New("too many failed attempts, request a new code later")
+	}
+
+	record, err := s.repo.FindByReceiverAndCodeAndType(receiver, code, otpType)
+	if err != nil || record == nil {
 		return false, errors.New("otp not found")
 	}
 	if record.ExpiresAt.Before(time.Now().UTC()) {
@@ -119,12 +125,19 @@ func (s *OTPServiceImpl) ValidateCode(receiver string, otpType otp.OtpType, code
 		return false, errors.New("otp used already")
 	}
 
-	// Mark OTP as used
-	record.Used = true
-	if err := s.repo.Update(record); err != nil {
+	// Mark OTP as used, only one of two requests with the same code gets it
+	marked, err := s.repo.MarkUsed(record.ID)
+	if err != nil {
 		return false, errors.New("cannot mark otp as used")
 	}
+	if !marked {
+		return false, errors.New("otp used already")
+	}
+	record.Used = true
 
+	if err := s.ResetVerify(receiver); err != nil {
+		logger.Error("otp attempts not reset: ", err.Error())
+	}
 	_ = s.repo.DeleteExpiredOtps()
 	return true, nil
 }
@@ -172,6 +185,20 @@ func (s *OTPServiceImpl) MarkSend(receiver string) error {
 	return s.limiter.MarkSend(receiver, s.codeTTL)
 }
 
+func (s *OTPServiceImpl) AttemptVerify(receiver string) (bool, error) {
+	if s.limiter == nil {
+		return true, nil
+	}
+	return s.limiter.AttemptVerify(receiver, s.codeTTL)
+}
+
+func (s *OTPServiceImpl) ResetVerify(receiver string) error {
+	if s.limiter == nil {
+		return nil
+	}
+	return s.limiter.ResetVerify(receiver)
+}
+
 // Hard limit Check by receiver and type
 func (s *OTPServiceImpl) CheckHardLimit(receiver string, otpType otp.OtpType) (bool, error) {
 	if s.maxOTPPerReceiver > 0 {
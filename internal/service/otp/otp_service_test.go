@@ -0,0 +1,135 @@
+package otp
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/yasinsaee/go-user-service/internal/domain/otp"
+	ratelimiter "github.com/yasinsaee/go-user-service/internal/service/otp/redis"
+	"github.com/yasinsaee/go-user-service/pkg/redis"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryOTPs struct {
+	otp.OTPRepository
+	codes otp.Otps
+}
+
+func (r *memoryOTPs) Create(o *otp.Otp) error {
+	o.ID = primitive.NewObjectID()
+	r.codes = append(r.codes, *o)
+	return nil
+}
+
+func (r *memoryOTPs) FindByReceiverAndCodeAndType(receiver, code string, otpType otp.OtpType) (*otp.Otp, error) {
+	for _, o := range r.codes {
+		if o.Receiver == receiver && o.Code == code && o.Type == otpType {
+			return &o, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *memoryOTPs) MarkUsed(id any) (bool, error) {
+	for i, o := range r.codes {
+		if o.ID == id && !o.Used {
+			r.codes[i].Used = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryOTPs) DeleteExpiredOtps() error {
+	return nil
+}
+
+func newTestService(t *testing.T, maxAttempts int) (*OTPServiceImpl, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	redis.Init(redis.Config{Addr: mr.Addr()})
+	limiter := ratelimiter.NewRedisOTPRateLimiter(3, maxAttempts)
+	s := &OTPServiceImpl{repo: &memoryOTPs{}, limiter: limiter, codeTTL: 2 * time.Minute}
+	return s, mr
+}
+
+func TestValidateCodeLocksAfterFailedAttempts(t *testing.T) {
+	s, mr := newTestService(t, 3)
+	const phone = "+15550100"
+	if err := s.SaveCode(phone, otp.OtpTypeLogin, "123456"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := s.ValidateCode(phone, otp.OtpTypeLogin, "000000"); ok {
+			t.Fatal("expected a wrong code to fail")
+		}
+	}
+	if ok, err := s.ValidateCode(phone, otp.OtpTypeLogin, "123456"); ok || err == nil {
+		t.Fatal("expected the right code to be refused once the attempts are used up")
+	}
+	if ok, _ := s.ValidateCode("+15550101", otp.OtpTypeLogin, "000000"); ok {
+		t.Fatal("expected a wrong code to fail")
+	}
+	if err := s.SaveCode("+15550101", otp.OtpTypeLogin, "654321"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.ValidateCode("+15550101", otp.OtpTypeLogin, "654321"); !ok || err != nil {
+		t.Fatalf("expected other numbers to keep their attempts, got %v, %v", ok, err)
+	}
+
+	// the lock ends with the code it was guessing
+	mr.FastForward(2 * time.Minute)
+	if ok, err := s.ValidateCode(phone, otp.OtpTypeLogin, "123456"); !ok || err != nil {
+		t.Fatalf("expected the attempts to reset, got %v, %v", ok, err)
+	}
+}
+
+func TestValidateCodeOnce(t *testing.T) {
+	s, _ := newTestService(t, 3)
+	const phone = "+15550100"
+	if err := s.SaveCode(phone, otp.OtpTypeLogin, "123456"); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := s.ValidateCode(phone, otp.OtpTypeForgotPassword, "123456"); ok || err == nil {
+		t.Fatal("expected a login code not to reset a password")
+	}
+	if ok, err := s.ValidateCode(phone, otp.OtpTypeLogin, "123456"); !ok || err != nil {
+		t.Fatalf("expected the code to verify, got %v, %v", ok, err)
+	}
+	if ok, err := s.ValidateCode(phone, otp.OtpTypeLogin, "123456"); ok || err == nil {
+		t.Fatal("expected a used code to be refused")
+	}
+}
+
+// staleOTPs finds codes as they were before any of them was used, like two requests that
+// both looked the code up before either marked it.
+type staleOTPs struct {
+	*memoryOTPs
+}
+
+func (r staleOTPs) FindByReceiverAndCodeAndType(receiver, code string, otpType otp.OtpType) (*otp.Otp, error) {
+	o, err := r.memoryOTPs.FindByReceiverAndCodeAndType(receiver, code, otpType)
+	if err == nil {
+		o.Used = false
+	}
+	return o, err
+}
+
+func TestValidateCodeOnceWhenFoundTwice(t *testing.T) {
+	s, _ := newTestService(t, 3)
+	s.repo = staleOTPs{&memoryOTPs{}}
+	const phone = "+15550100"
+	if err := s.SaveCode(phone, otp.OtpTypeLogin, "123456"); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := s.ValidateCode(phone, otp.OtpTypeLogin, "123456"); !ok || err != nil {
+		t.Fatalf("expected the code to verify, got %v, %v", ok, err)
+	}
+	if ok, err := s.ValidateCode(phone, otp.OtpTypeLogin, "123456"); ok || err == nil {
+		t.Fatal("expected the second request with the code to be refused")
+	}
+}
@@ -8,13 +8,23 @@ import (
 	"github.com/yasinsaee/go-user-service/pkg/redis"
 )
 
+// attemptScript counts an attempt and restarts its window at once, a counter without expiry
+// would lock the receiver out for good. KEYS is the counter, ARGV the window in milliseconds.
+var attemptScript = redis.NewScript(`
+local attempts = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[1])
+return attempts
+`)
+
 type RedisOTPRateLimiter struct {
-	limit int
+	limit       int
+	maxAttempts int
 }
 
-func NewRedisOTPRateLimiter(limit int) *RedisOTPRateLimiter {
+func NewRedisOTPRateLimiter(limit, maxAttempts int) *RedisOTPRateLimiter {
 	return &RedisOTPRateLimiter{
-		limit: limit,
+		limit:       limit,
+		maxAttempts: maxAttempts,
 	}
 }
 
@@ -51,6 +61,23 @@ func (r *RedisOTPRateLimiter) MarkSend(receiver string, ttl time.Duration) error
 	return nil
 }
 
+func (r *RedisOTPRateLimiter) AttemptVerify(receiver string, ttl time.Duration) (bool, error) {
+	if r.maxAttempts <= 0 {
+		return true, nil
+	}
+
+	attempts, err := redis.RunScript(attemptScript, []string{"otp_attempts:" + receiver}, ttl.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+
+	return attempts.(int64) <= int64(r.maxAttempts), nil
+}
+
+func (r *RedisOTPRateLimiter) ResetVerify(receiver string) error {
+	return redis.Remove("otp_attempts:" + receiver)
+}
+
 func redisClientStrToInt(val string) (int, error) {
 	var count int
 	_, err := fmt.Sscanf(val, "%d", &count)
@@ -0,0 +1,299 @@
+package passkey
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/yasinsaee/go-user-service/internal/domain/passkey"
+	"github.com/yasinsaee/go-user-service/internal/domain/passkey/config"
+	"github.com/yasinsaee/go-user-service/internal/domain/security"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/pkg/logger"
+	"github.com/yasinsaee/go-user-service/pkg/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ceremonyTTL is how long the user has to answer the authenticator's prompt
+const ceremonyTTL = 5 * time.Minute
+
+// passkeyServiceImpl is the concrete implementation of PasskeyService.
+type passkeyServiceImpl struct {
+	webAuthn *webauthn.WebAuthn
+	users    user.UserRepository
+	store    passkey.CeremonyStore
+	events   security.EventPublisher
+}
+
+// NewPasskeyService creates a new instance of PasskeyService.
+func NewPasskeyService(cfg config.PasskeyConfig, users user.UserRepository, store passkey.CeremonyStore, events security.EventPublisher) (passkey.PasskeyService, error) {
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.RPID,
+		RPDisplayName: cfg.RPDisplayName,
+		RPOrigins:     cfg.RPOrigins,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &passkeyServiceImpl{
+		webAuthn: webAuthn,
+		users:    users,
+		store:    store,
+		events:   events,
+	}, nil
+}
+
+// webAuthnUser adapts a user to the relying party library.
+type webAuthnUser struct {
+	*user.User
+}
+
+func (u webAuthnUser) WebAuthnID() []byte {
+	return u.ID[:]
+}
+
+func (u webAuthnUser) WebAuthnName() string {
+	return u.Username
+}
+
+func (u webAuthnUser) WebAuthnDisplayName() string {
+	if name := u.FirstName + " " + u.LastName; name != " " {
+		return name
+	}
+	return u.Username
+}
+
+func (u webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.Passkeys))
+	for _, p := range u.Passkeys {
+		credential := webauthn.Credential{
+			ID:              p.ID,
+			PublicKey:       p.PublicKey,
+			AttestationType: p.AttestationType,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: p.BackupEligible,
+				BackupState:    p.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    p.AAGUID,
+				SignCount: p.SignCount,
+			},
+		}
+		for _, t := range p.Transports {
+			credential.Transport = append(credential.Transport, protocol.AuthenticatorTransport(t))
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials
+}
+
+func (s *passkeyServiceImpl) startCeremony(userID string, session *webauthn.SessionData) (string, error) {
+	id, err := util.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	c := &passkey.Ceremony{
+		ID:        id,
+		UserID:    userID,
+		Session:   *session,
+		ExpiresAt: time.Now().UTC().Add(ceremonyTTL),
+	}
+	if err := s.store.Save(c); err != nil {
+		return "", err
+	}
+	return c.ID, nil
+}
+
+func (s *passkeyServiceImpl) BeginRegistration(u *user.User) ([]byte, string, error) {
+	wu := webAuthnUser{u}
+	creation, session, err := s.webAuthn.BeginRegistration(wu,
+		// a passkey is found by the browser on its own and proves the user, not just their presence
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		}),
+		webauthn.WithExclusions(webauthn.Credentials(wu.WebAuthnCredentials()).CredentialDescriptors()),
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	options, err := json.Marshal(creation)
+	if err != nil {
+		return nil, "", err
+	}
+	id, err := s.startCeremony(u.ID.Hex(), session)
+	if err != nil {
+		return nil, "", err
+	}
+	return options, id, nil
+}
+
+func (s *passkeyServiceImpl) FinishRegistration(u *user.User, ceremonyID, nickname string, response []byte) (*user.Passkey, error) {
+	c, err := s.store.Take(ceremonyID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil || c.UserID != u.ID.Hex() || time.Now().UTC().After(c.ExpiresAt) {
+		return nil, passkey.ErrInvalidCeremony
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, passkey.ErrInvalidPasskey
+	}
+	credential, err := s.webAuthn.CreateCredential(webAuthnUser{u}, c.Session, parsed)
+	if err != nil {
+		logger.Error("passkey registration failed: ", err.Error())
+		return nil, passkey.ErrInvalidPasskey
+	}
+
+	if nickname == "" {
+		nickname = "Passkey " + time.Now().UTC().Format("2006-01-02")
+	}
+	p := user.Passkey{
+		ID:              credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+		Nickname:        nickname,
+		CreatedAt:       time.Now().UTC(),
+	}
+	for _, t := range credential.Transport {
+		p.Transports = append(p.Transports, string(t))
+	}
+
+	u.Passkeys = append(u.Passkeys, p)
+	if err := s.users.Update(u); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *passkeyServiceImpl) BeginLogin() ([]byte, string, error) {
+	assertion, session, err := s.webAuthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		return nil, "", err
+	}
+
+	options, err := json.Marshal(assertion)
+	if err != nil {
+		return nil, "", err
+	}
+	id, err := s.startCeremony("", session)
+	if err != nil {
+		return nil, "", err
+	}
+	return options, id, nil
+}
+
+func (s *passkeyServiceImpl) FinishLogin(ceremonyID string, response []byte) (*user.User, error) {
+	c, err := s.store.Take(ceremonyID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil || c.UserID != "" || time.Now().UTC().After(c.ExpiresAt) {
+		return nil, passkey.ErrInvalidCeremony
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, passkey.ErrInvalidPasskey
+	}
+
+	// the user handle is the user id, see webAuthnUser
+	var owner *user.User
+	found, credential, err := s.webAuthn.ValidatePasskeyLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+		if len(userHandle) != len(primitive.ObjectID{}) {
+			return nil, passkey.ErrInvalidPasskey
+		}
+		u, err := s.users.FindByID(primitive.ObjectID(userHandle))
+		if err != nil {
+			return nil, err
+		}
+		owner = u
+		return webAuthnUser{u}, nil
+	}, c.Session, parsed)
+	if err != nil || found == nil {
+		if err != nil {
+			logger.Error("passkey login failed: ", err.Error())
+		}
+		return nil, passkey.ErrInvalidPasskey
+	}
+
+	i := indexOf(owner.Passkeys, credential.ID)
+	if i < 0 {
+		return nil, passkey.ErrInvalidPasskey
+	}
+	p := &owner.Passkeys[i]
+	if p.CloneWarning {
+		return nil, passkey.ErrCloneDetected
+	}
+	if !owner.Active() {
+		return nil, user.ErrDeactivated
+	}
+	if credential.Authenticator.CloneWarning {
+		p.CloneWarning = true
+		if err := s.users.Update(owner); err != nil {
+			return nil, err
+		}
+		s.publishClone(owner, p)
+		return nil, passkey.ErrCloneDetected
+	}
+
+	p.SignCount = credential.Authenticator.SignCount
+	p.BackupState = credential.Flags.BackupState
+	p.LastUsedAt = time.Now().UTC()
+	owner.LastLogin = p.LastUsedAt
+	if err := s.users.Update(owner); err != nil {
+		return nil, err
+	}
+	return owner, nil
+}
+
+func (s *passkeyServiceImpl) DeletePasskey(u *user.User, id string) error {
+	rawID, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return passkey.ErrPasskeyNotFound
+	}
+	i := indexOf(u.Passkeys, rawID)
+	if i < 0 {
+		return passkey.ErrPasskeyNotFound
+	}
+
+	u.Passkeys = append(u.Passkeys[:i], u.Passkeys[i+1:]...)
+	return s.users.Update(u)
+}
+
+func (s *passkeyServiceImpl) publishClone(u *user.User, p *user.Passkey) {
+	event := security.Event{
+		Type:   security.EventPasskeyCloned,
+		UserID: u.ID.Hex(),
+		Details: map[string]string{
+			"passkey_id": base64.RawURLEncoding.EncodeToString(p.ID),
+			"nickname":   p.Nickname,
+		},
+		OccurredAt: time.Now().UTC(),
+	}
+	if err := s.events.Publish(event); err != nil {
+		// the passkey is already refused, a lost event must not let it through
+		logger.Error("failed to publish security event: ", err.Error())
+	}
+}
+
+func indexOf(passkeys []user.Passkey, id []byte) int {
+	for i := range passkeys {
+		if bytes.Equal(passkeys[i].ID, id) {
+			return i
+		}
+	}
+	return -1
+}
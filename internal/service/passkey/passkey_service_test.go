@@ -0,0 +1,291 @@
+package passkey
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/yasinsaee/go-user-service/internal/domain/passkey"
+	"github.com/yasinsaee/go-user-service/internal/domain/passkey/config"
+	"github.com/yasinsaee/go-user-service/internal/domain/security"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://example.com"
+)
+
+// softAuthenticator is a platform authenticator holding a single P-256 passkey.
+type softAuthenticator struct {
+	key       *ecdsa.PrivateKey
+	id        []byte
+	userID    []byte
+	signCount uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := make([]byte, 16)
+	rand.Read(id)
+	return &softAuthenticator{key: key, id: id}
+}
+
+// authData builds the authenticator data with user presence and verification, attested carries the credential.
+func (a *softAuthenticator) authData(t *testing.T, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+	flags := byte(0x01 | 0x04) // UP, UV
+	if attested {
+		flags |= 0x40 // AT
+	}
+
+	data := append(rpIDHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if !attested {
+		return data
+	}
+
+	coseKey, err := webauthncbor.Marshal(map[int]any{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = append(data, make([]byte, 16)...) // AAGUID
+	data = binary.BigEndian.AppendUint16(data, uint16(len(a.id)))
+	data = append(data, a.id...)
+	return append(data, coseKey...)
+}
+
+func clientData(t *testing.T, ceremony, options string) []byte {
+	var parsed struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+			User      struct {
+				ID string `json:"id"`
+			} `json:"user"`
+		} `json:"publicKey"`
+	}
+	if err := json.Unmarshal([]byte(options), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(map[string]any{
+		"type":      ceremony,
+		"challenge": parsed.PublicKey.Challenge,
+		"origin":    testOrigin,
+	})
+	return data
+}
+
+func (a *softAuthenticator) create(t *testing.T, options []byte) []byte {
+	var parsed struct {
+		PublicKey struct {
+			User struct {
+				ID string `json:"id"`
+			} `json:"user"`
+		} `json:"publicKey"`
+	}
+	json.Unmarshal(options, &parsed)
+	a.userID, _ = base64.RawURLEncoding.DecodeString(parsed.PublicKey.User.ID)
+
+	attestation, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(t, true),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	response, _ := json.Marshal(map[string]any{
+		"id":    b64(a.id),
+		"rawId": b64(a.id),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    b64(clientData(t, "webauthn.create", string(options))),
+			"attestationObject": b64(attestation),
+			"transports":        []string{"internal"},
+		},
+	})
+	return response
+}
+
+func (a *softAuthenticator) get(t *testing.T, options []byte) []byte {
+	a.signCount++
+	authData := a.authData(t, false)
+	cData := clientData(t, "webauthn.get", string(options))
+
+	hash := sha256.Sum256(cData)
+	digest := sha256.Sum256(append(authData, hash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	response, _ := json.Marshal(map[string]any{
+		"id":    b64(a.id),
+		"rawId": b64(a.id),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    b64(cData),
+			"authenticatorData": b64(authData),
+			"signature":         b64(signature),
+			"userHandle":        b64(a.userID),
+		},
+	})
+	return response
+}
+
+type memoryUsers struct {
+	user.UserRepository
+	users map[primitive.ObjectID]*user.User
+}
+
+func (r *memoryUsers) FindByID(id any) (*user.User, error) {
+	if u, ok := r.users[id.(primitive.ObjectID)]; ok {
+		return u, nil
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *memoryUsers) Update(u *user.User) error {
+	r.users[u.ID] = u
+	return nil
+}
+
+type memoryCeremonies map[string]*passkey.Ceremony
+
+func (m memoryCeremonies) Save(c *passkey.Ceremony) error {
+	m[c.ID] = c
+	return nil
+}
+
+func (m memoryCeremonies) Take(id string) (*passkey.Ceremony, error) {
+	c := m[id]
+	delete(m, id)
+	return c, nil
+}
+
+type recordedEvents []security.Event
+
+func (r *recordedEvents) Publish(event security.Event) error {
+	*r = append(*r, event)
+	return nil
+}
+
+func newTestService(t *testing.T) (passkey.PasskeyService, *user.User, *recordedEvents) {
+	u := &user.User{ID: primitive.NewObjectID(), Username: "jane"}
+	events := &recordedEvents{}
+	s, err := NewPasskeyService(config.PasskeyConfig{
+		RPID:          testRPID,
+		RPDisplayName: "Example",
+		RPOrigins:     []string{testOrigin},
+	}, &memoryUsers{users: map[primitive.ObjectID]*user.User{u.ID: u}}, memoryCeremonies{}, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, u, events
+}
+
+func register(t *testing.T, s passkey.PasskeyService, u *user.User, a *softAuthenticator) {
+	options, id, err := s.BeginRegistration(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := s.FinishRegistration(u, id, "laptop", a.create(t, options))
+	if err != nil {
+		t.Fatalf("FinishRegistration() error = %v", err)
+	}
+	if p.Nickname != "laptop" || len(u.Passkeys) != 1 {
+		t.Fatalf("passkeys = %+v, want the registered one", u.Passkeys)
+	}
+}
+
+func login(s passkey.PasskeyService, t *testing.T, a *softAuthenticator) (*user.User, error) {
+	options, id, err := s.BeginLogin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s.FinishLogin(id, a.get(t, options))
+}
+
+func TestPasskeyRegistrationAndLogin(t *testing.T) {
+	s, u, _ := newTestService(t)
+	a := newSoftAuthenticator(t)
+	register(t, s, u, a)
+
+	got, err := login(s, t, a)
+	if err != nil {
+		t.Fatalf("FinishLogin() error = %v", err)
+	}
+	if got.ID != u.ID || u.Passkeys[0].SignCount != 1 || u.Passkeys[0].LastUsedAt.IsZero() {
+		t.Errorf("login = %v, passkey = %+v, want the user and an updated passkey", got.ID, u.Passkeys[0])
+	}
+
+	// a ceremony is answered once
+	options, id, _ := s.BeginLogin()
+	response := a.get(t, options)
+	if _, err := s.FinishLogin(id, response); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.FinishLogin(id, response); !errors.Is(err, passkey.ErrInvalidCeremony) {
+		t.Errorf("replayed FinishLogin() error = %v, want %v", err, passkey.ErrInvalidCeremony)
+	}
+}
+
+func TestPasskeyRejectsOtherKeys(t *testing.T) {
+	s, u, _ := newTestService(t)
+	a := newSoftAuthenticator(t)
+	register(t, s, u, a)
+
+	// same credential id and user, different private key
+	forged := newSoftAuthenticator(t)
+	forged.id, forged.userID = a.id, a.userID
+	if _, err := login(s, t, forged); !errors.Is(err, passkey.ErrInvalidPasskey) {
+		t.Errorf("FinishLogin(forged) error = %v, want %v", err, passkey.ErrInvalidPasskey)
+	}
+}
+
+func TestPasskeyCloneDetection(t *testing.T) {
+	s, u, events := newTestService(t)
+	a := newSoftAuthenticator(t)
+	register(t, s, u, a)
+
+	a.signCount = 5
+	if _, err := login(s, t, a); err != nil {
+		t.Fatal(err)
+	}
+
+	// a copy of the key that did not see the last logins
+	a.signCount = 2
+	if _, err := login(s, t, a); !errors.Is(err, passkey.ErrCloneDetected) {
+		t.Fatalf("FinishLogin(clone) error = %v, want %v", err, passkey.ErrCloneDetected)
+	}
+	if !u.Passkeys[0].CloneWarning || len(*events) != 1 || (*events)[0].Type != security.EventPasskeyCloned {
+		t.Errorf("passkey = %+v, events = %v, want it flagged and reported", u.Passkeys[0], *events)
+	}
+
+	// the flagged passkey stays refused even with a higher count
+	a.signCount = 10
+	if _, err := login(s, t, a); !errors.Is(err, passkey.ErrCloneDetected) {
+		t.Errorf("FinishLogin(flagged) error = %v, want %v", err, passkey.ErrCloneDetected)
+	}
+}
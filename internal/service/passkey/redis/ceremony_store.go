@@ -0,0 +1,46 @@
+package passkey_ceremony_store
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/passkey"
+	"github.com/yasinsaee/go-user-service/pkg/redis"
+)
+
+type ceremonyStoreImpl struct{}
+
+// NewCeremonyStore returns a new instance of CeremonyStore.
+func NewCeremonyStore() *ceremonyStoreImpl {
+	return &ceremonyStoreImpl{}
+}
+
+func (s *ceremonyStoreImpl) Save(c *passkey.Ceremony) error {
+	ttl := time.Until(c.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return redis.Set("passkey_ceremony:"+c.ID, data, ttl)
+}
+
+func (s *ceremonyStoreImpl) Take(id string) (*passkey.Ceremony, error) {
+	val, err := redis.GetDel("passkey_ceremony:" + id)
+	if err == redis.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &passkey.Ceremony{}
+	if err := json.Unmarshal([]byte(val), c); err != nil {
+		return nil, err
+	}
+	c.ID = id
+	return c, nil
+}
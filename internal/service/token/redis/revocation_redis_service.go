@@ -0,0 +1,72 @@
+package token_revocation_store
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/pkg/redis"
+)
+
+type revocationStoreImpl struct {
+	maxTokenLifetime time.Duration
+}
+
+// NewRevocationStore returns a new instance of RevocationStore.
+// maxTokenLifetime bounds how long a per-user revocation has to be kept.
+func NewRevocationStore(maxTokenLifetime time.Duration) *revocationStoreImpl {
+	return &revocationStoreImpl{
+		maxTokenLifetime: maxTokenLifetime,
+	}
+}
+
+func (s *revocationStoreImpl) Revoke(tokenID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		// already expired, nothing left to revoke
+		return nil
+	}
+	return redis.Set("revoked_token:"+tokenID, "1", ttl)
+}
+
+func (s *revocationStoreImpl) IsRevoked(tokenID string) (bool, error) {
+	return redis.Exists("revoked_token:" + tokenID)
+}
+
+func (s *revocationStoreImpl) RevokeBefore(userID string, before time.Time) error {
+	key := "revoked_before:" + userID
+
+	// never move the cut-off backwards
+	current, err := s.RevokedBefore(userID)
+	if err != nil {
+		return err
+	}
+	if current.After(before) {
+		return nil
+	}
+
+	return redis.Set(key, strconv.FormatInt(before.Unix(), 10), s.maxTokenLifetime)
+}
+
+func (s *revocationStoreImpl) RevokedBefore(userID string) (time.Time, error) {
+	val, err := redis.Get("revoked_before:" + userID)
+	if err == redis.ErrKeyNotFound {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	unix, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(unix, 0).UTC(), nil
+}
+
+func (s *revocationStoreImpl) RevokeSession(sessionID string) error {
+	return redis.Set("revoked_session:"+sessionID, "1", s.maxTokenLifetime)
+}
+
+func (s *revocationStoreImpl) IsSessionRevoked(sessionID string) (bool, error) {
+	return redis.Exists("revoked_session:" + sessionID)
+}
@@ -0,0 +1,92 @@
+package token_revocation_store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/yasinsaee/go-user-service/pkg/redis"
+)
+
+func startRedis(t *testing.T) *miniredis.Miniredis {
+	mr := miniredis.RunT(t)
+	redis.Init(redis.Config{Addr: mr.Addr()})
+	return mr
+}
+
+func TestRevokeExpiresWithTheToken(t *testing.T) {
+	mr := startRedis(t)
+	s := NewRevocationStore(time.Hour)
+
+	if err := s.Revoke("jti-1", time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Revoke("jti-2", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	if revoked, err := s.IsRevoked("jti-1"); err != nil || !revoked {
+		t.Fatalf("expected the token to be revoked, got %v, %v", revoked, err)
+	}
+	if mr.Exists("revoked_token:jti-2") {
+		t.Fatal("expected an expired token not to be stored")
+	}
+
+	mr.FastForward(2 * time.Minute)
+	if revoked, _ := s.IsRevoked("jti-1"); revoked {
+		t.Fatal("expected the revocation to end with the token")
+	}
+}
+
+func TestRevokeBefore(t *testing.T) {
+	mr := startRedis(t)
+	s := NewRevocationStore(time.Hour)
+
+	if before, err := s.RevokedBefore("u1"); err != nil || !before.IsZero() {
+		t.Fatalf("expected no cut-off yet, got %v, %v", before, err)
+	}
+
+	later := time.Now().UTC().Truncate(time.Second)
+	earlier := later.Add(-time.Minute)
+	if err := s.RevokeBefore("u1", later); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RevokeBefore("u1", earlier); err != nil {
+		t.Fatal(err)
+	}
+	if before, _ := s.RevokedBefore("u1"); !before.Equal(later) {
+		t.Fatalf("expected the cut-off not to move backwards, got %v want %v", before, later)
+	}
+	if before, _ := s.RevokedBefore("u2"); !before.IsZero() {
+		t.Fatal("expected other users to be left alone")
+	}
+
+	// no token outlives the longest lifetime, so neither has the cut-off to
+	if ttl := mr.TTL("revoked_before:u1"); ttl != time.Hour {
+		t.Fatalf("expected the cut-off to be kept for the longest token lifetime, got %v", ttl)
+	}
+	mr.FastForward(time.Hour)
+	if before, _ := s.RevokedBefore("u1"); !before.IsZero() {
+		t.Fatal("expected the cut-off to expire")
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	mr := startRedis(t)
+	s := NewRevocationStore(time.Hour)
+
+	if err := s.RevokeSession("sid-1"); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, err := s.IsSessionRevoked("sid-1"); err != nil || !revoked {
+		t.Fatalf("expected the session to be revoked, got %v, %v", revoked, err)
+	}
+	if revoked, _ := s.IsSessionRevoked("sid-2"); revoked {
+		t.Fatal("expected other sessions to be left alone")
+	}
+
+	mr.FastForward(time.Hour)
+	if revoked, _ := s.IsSessionRevoked("sid-1"); revoked {
+		t.Fatal("expected the revocation to end with the longest token lifetime")
+	}
+}
@@ -0,0 +1,145 @@
+package token
+
+import (
+	"strings"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/token"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/pkg/jwt"
+)
+
+type tokenService struct {
+	revocations   token.RevocationStore  // Redis-based revocation list
+	refreshTokens user.RefreshTokenStore // Redis-based refresh token store
+}
+
+// NewTokenService returns a new instance of TokenService.
+func NewTokenService(revocations token.RevocationStore, refreshTokens user.RefreshTokenStore) token.TokenService {
+	return &tokenService{
+		revocations:   revocations,
+		refreshTokens: refreshTokens,
+	}
+}
+
+func (s *tokenService) RevokeToken(signed string) error {
+	claims, err := jwt.ParseRegisteredClaims(signed)
+	if err != nil {
+		return err
+	}
+	// legacy tokens have no jti, they are only revoked with every other token of their user
+	if claims.TokenID == "" {
+		return nil
+	}
+	return s.revocations.Revoke(claims.TokenID, time.Unix(claims.ExpiresAt, 0))
+}
+
+func (s *tokenService) RevokeUserTokens(userID string, before time.Time) error {
+	return s.revocations.RevokeBefore(userID, before)
+}
+
+func (s *tokenService) RevokeSession(sessionID string) error {
+	return s.revocations.RevokeSession(sessionID)
+}
+
+func (s *tokenService) IsRevoked(claims jwt.RegisteredClaims) (bool, error) {
+	if claims.TokenID != "" {
+		revoked, err := s.revocations.IsRevoked(claims.TokenID)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	if claims.SessionID != "" {
+		revoked, err := s.revocations.IsSessionRevoked(claims.SessionID)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	before, err := s.revocations.RevokedBefore(claims.Subject)
+	if err != nil {
+		return false, err
+	}
+	return claims.IssuedAt < before.Unix(), nil
+}
+
+func (s *tokenService) Introspect(signed string, hint jwt.TokenType) (*token.Introspection, error) {
+	if hint == jwt.TokenTypeRefresh {
+		if result, err := s.introspectRefresh(signed); err != nil || result.Active {
+			return result, err
+		}
+		return s.introspectAccess(signed), nil
+	}
+
+	if result := s.introspectAccess(signed); result.Active {
+		return result, nil
+	}
+	return s.introspectRefresh(signed)
+}
+
+func (s *tokenService) introspectAccess(signed string) *token.Introspection {
+	claims, err := jwt.ValidateAccessToken(signed)
+	if err != nil {
+		return &token.Introspection{Active: false}
+	}
+
+	principal := jwt.PrincipalUser
+	if claims.IsClient() {
+		principal = jwt.PrincipalClient
+	}
+
+	return &token.Introspection{
+		Active:    true,
+		TokenType: claims.Type,
+		Principal: principal,
+		ClientID:  claims.ClientID,
+		Subject:   claims.Subject,
+		Username:  claims.Username,
+		Roles:     claims.Roles,
+		Access:    claims.Access,
+		TokenID:   claims.TokenID,
+		Issuer:    claims.Issuer,
+		Audience:  claims.Audience,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0).UTC(),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC(),
+
+		Authentication: claims.Authentication,
+	}
+}
+
+// introspectRefresh also requires the token to still be in the store,
+// rotated or logged out refresh tokens are not active even though their signature is.
+func (s *tokenService) introspectRefresh(signed string) (*token.Introspection, error) {
+	claims, err := jwt.ValidateRefreshToken(signed)
+	if err != nil {
+		return &token.Introspection{Active: false}, nil
+	}
+
+	exists, err := s.refreshTokens.Exists(claims.ID, trimBearer(signed))
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return &token.Introspection{Active: false}, nil
+	}
+
+	return &token.Introspection{
+		Active:    true,
+		TokenType: claims.Type,
+		Principal: jwt.PrincipalUser,
+		Subject:   claims.Subject,
+		Username:  claims.Username,
+		TokenID:   claims.TokenID,
+		Issuer:    claims.Issuer,
+		Audience:  claims.Audience,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0).UTC(),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC(),
+
+		Authentication: claims.Authentication,
+	}, nil
+}
+
+func trimBearer(token string) string {
+	return strings.TrimPrefix(token, "Bearer ")
+}
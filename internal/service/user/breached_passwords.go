@@ -0,0 +1,60 @@
+package user
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+)
+
+// rangeFiles looks passwords up in a local copy of the Pwned Passwords range files, as
+// written by haveibeenpwned-downloader. The SHA-1 of a password is split like in the
+// k-anonymity API: the first five hex digits name the file, e.g. 5BAA6.txt, whose
+// lines hold the other 35 digits and a count, e.g. "1E4C9B93F3F0682250B6CF8331B7EE68FD8:3".
+type rangeFiles struct {
+	dir string
+}
+
+// NewBreachedPasswords returns a BreachedPasswords reading the range files in dir.
+func NewBreachedPasswords(dir string) (user.BreachedPasswords, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return &rangeFiles{dir: dir}, nil
+}
+
+func (r *rangeFiles) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	f, err := os.Open(filepath.Join(r.dir, prefix+".txt"))
+	// a partial copy may leave ranges out, they are not known to be breached
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), ":")
+		if strings.EqualFold(strings.TrimSpace(line), suffix) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
@@ -0,0 +1,159 @@
+package user
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/role"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/internal/domain/user/config"
+)
+
+// minPersonalInfoLength keeps short values, e.g. a two letter username, from banning half the passwords
+const minPersonalInfoLength = 3
+
+// passwordPolicy is the concrete implementation of PasswordPolicy.
+type passwordPolicy struct {
+	config   config.PasswordPolicyConfig
+	breached user.BreachedPasswords // nil when no breached list is configured
+	hasher   user.PasswordHasher    // compares with the remembered passwords
+	roles    role.RoleRepository    // names the roles of the history and expiry settings
+}
+
+// NewPasswordPolicy returns a new instance of PasswordPolicy, breached may be nil.
+func NewPasswordPolicy(config config.PasswordPolicyConfig, breached user.BreachedPasswords, hasher user.PasswordHasher, roles role.RoleRepository) user.PasswordPolicy {
+	return &passwordPolicy{
+		config:   config,
+		breached: breached,
+		hasher:   hasher,
+		roles:    roles,
+	}
+}
+
+func (p *passwordPolicy) Validate(password string, u *user.User) error {
+	var violations []user.PasswordViolation
+	violate := func(rule, format string, args ...any) {
+		violations = append(violations, user.PasswordViolation{Rule: rule, Description: fmt.Sprintf(format, args...)})
+	}
+
+	if utf8.RuneCountInString(password) < p.config.MinLength {
+		violate(user.PasswordTooShort, "must be at least %d characters long", p.config.MinLength)
+	}
+	if p.config.MaxLength > 0 && len(password) > p.config.MaxLength {
+		violate(user.PasswordTooLong, "must be at most %d bytes long", p.config.MaxLength)
+	}
+	for _, class := range p.config.RequiredClasses {
+		if !strings.ContainsFunc(password, classes[class]) {
+			violate(user.PasswordMissingClass, "must contain a %s character", class)
+		}
+	}
+	if field := personalInfoIn(password, u); field != "" {
+		violate(user.PasswordContainsPersonalInfo, "must not contain your %s", field)
+	}
+
+	if p.reused(password, u) {
+		violate(user.PasswordReused, "must not be one of your last %d passwords", p.config.HistorySize)
+	}
+
+	if p.breached != nil {
+		breached, err := p.breached.Contains(password)
+		if err != nil {
+			return err
+		}
+		if breached {
+			violate(user.PasswordBreached, "has appeared in a data breach, choose another one")
+		}
+	}
+
+	if len(violations) > 0 {
+		return &user.PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+func (p *passwordPolicy) ChangeReason(u *user.User) string {
+	if u.MustChangePassword {
+		return user.PasswordChangeRequired
+	}
+	if p.config.MaxAge <= 0 || (len(p.config.MaxAgeRoles) > 0 && !p.hasRole(u, p.config.MaxAgeRoles)) {
+		return ""
+	}
+
+	changedAt := u.PasswordChangedAt
+	if changedAt.IsZero() {
+		changedAt = u.CreatedAt
+	}
+	if !changedAt.IsZero() && time.Since(changedAt) > p.config.MaxAge {
+		return user.PasswordExpired
+	}
+	return ""
+}
+
+func (p *passwordPolicy) HistorySize() int {
+	return p.config.HistorySize
+}
+
+// reused reports whether password is the stored user's current password or one of the
+// remembered ones, unless a role of the user is exempt.
+func (p *passwordPolicy) reused(password string, u *user.User) bool {
+	if p.config.HistorySize <= 0 || u == nil || u.ID.IsZero() || p.hasRole(u, p.config.HistoryExemptRoles) {
+		return false
+	}
+
+	hashes := append([]string{u.Password}, u.PasswordHistory...)
+	for _, hash := range hashes[:min(len(hashes), p.config.HistorySize)] {
+		if ok, _ := p.hasher.Verify(password, hash); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRole reports whether the user has one of the named roles.
+func (p *passwordPolicy) hasRole(u *user.User, names []string) bool {
+	if len(names) == 0 {
+		return false
+	}
+	for _, id := range u.Roles {
+		r, err := p.roles.FindByID(id)
+		if err == nil && slices.Contains(names, r.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+var classes = map[string]func(rune) bool{
+	config.ClassLower: unicode.IsLower,
+	config.ClassUpper: unicode.IsUpper,
+	config.ClassDigit: unicode.IsDigit,
+	config.ClassSymbol: func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	},
+}
+
+// personalInfoIn returns the name of the first account field found in the password, ignoring case.
+func personalInfoIn(password string, u *user.User) string {
+	if u == nil {
+		return ""
+	}
+	password = strings.ToLower(password)
+
+	localPart, _, _ := strings.Cut(u.Email, "@")
+	for _, field := range []struct{ name, value string }{
+		{"username", u.Username},
+		{"phone number", u.PhoneNumber},
+		{"email", u.Email},
+		{"email", localPart},
+	} {
+		value := strings.ToLower(strings.TrimSpace(field.value))
+		if utf8.RuneCountInString(value) >= minPersonalInfoLength && strings.Contains(password, value) {
+			return field.name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,176 @@
+package user
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/role"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/internal/domain/user/config"
+	"github.com/yasinsaee/go-user-service/pkg/password"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// cheap Argon2id, the tests are not about its cost
+var testHasher = password.NewHasher(password.NewArgon2id(password.Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}, nil))
+
+func testHash(t *testing.T, plain string) string {
+	t.Helper()
+	hash, err := testHasher.Hash(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+type memoryRoles struct {
+	role.RoleRepository
+	roles []*role.Role
+}
+
+func (r *memoryRoles) FindByID(id any) (*role.Role, error) {
+	for _, ro := range r.roles {
+		if ro.ID == id {
+			return ro, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func violatedRules(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var policyErr *user.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("expected a *PasswordPolicyError, got %v", err)
+	}
+	rules := make([]string, len(policyErr.Violations))
+	for i, v := range policyErr.Violations {
+		rules[i] = v.Rule
+	}
+	return rules
+}
+
+func TestPasswordPolicyReportsEveryRule(t *testing.T) {
+	policy := NewPasswordPolicy(config.PasswordPolicyConfig{
+		MinLength:       10,
+		MaxLength:       72,
+		RequiredClasses: []string{config.ClassUpper, config.ClassDigit, config.ClassSymbol},
+	}, nil, nil, nil)
+	u := &user.User{Username: "yasin", Email: "y.saee@example.com", PhoneNumber: "09120000000"}
+
+	rules := violatedRules(t, policy.Validate("yasin", u))
+	want := []string{user.PasswordTooShort, user.PasswordMissingClass, user.PasswordMissingClass, user.PasswordMissingClass, user.PasswordContainsPersonalInfo}
+	if !slices.Equal(rules, want) {
+		t.Fatalf("expected %v, got %v", want, rules)
+	}
+
+	if rules := violatedRules(t, policy.Validate("my-Y.SAEE-9pass", u)); !slices.Equal(rules, []string{user.PasswordContainsPersonalInfo}) {
+		t.Fatalf("expected the email local part to be found, got %v", rules)
+	}
+	if err := policy.Validate("Correct-Horse-9", u); err != nil {
+		t.Fatalf("expected the password to pass, got %v", err)
+	}
+}
+
+func TestBreachedPasswordsReadsRangeFiles(t *testing.T) {
+	dir := t.TempDir()
+	// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+	lines := "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n1E4C9B93F3F0682250B6CF8331B7EE68FD8:52256179\r\n"
+	if err := os.WriteFile(filepath.Join(dir, "5BAA6.txt"), []byte(lines), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	breached, err := NewBreachedPasswords(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := breached.Contains("password"); err != nil || !ok {
+		t.Fatalf("expected password to be breached, got %v, %v", ok, err)
+	}
+	if ok, err := breached.Contains("Correct-Horse-9"); err != nil || ok {
+		t.Fatalf("expected a missing range not to be breached, got %v, %v", ok, err)
+	}
+
+	policy := NewPasswordPolicy(config.PasswordPolicyConfig{MinLength: 8}, breached, nil, nil)
+	if rules := violatedRules(t, policy.Validate("password", nil)); !slices.Equal(rules, []string{user.PasswordBreached}) {
+		t.Fatalf("expected only the breach to be reported, got %v", rules)
+	}
+
+	if _, err := NewBreachedPasswords(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("expected a missing directory to be refused")
+	}
+}
+
+func TestPasswordPolicyRejectsReuse(t *testing.T) {
+	service := &role.Role{ID: primitive.NewObjectID(), Name: "service"}
+	policy := NewPasswordPolicy(config.PasswordPolicyConfig{
+		HistorySize:        3,
+		HistoryExemptRoles: []string{"service"},
+	}, nil, testHasher, &memoryRoles{roles: []*role.Role{service}})
+
+	u := &user.User{
+		ID:       primitive.NewObjectID(),
+		Password: testHash(t, "current-1"),
+		// only the two newest count, with the current one they are the last three
+		PasswordHistory: []string{testHash(t, "previous-2"), testHash(t, "previous-3"), testHash(t, "previous-4")},
+	}
+	for _, reused := range []string{"current-1", "previous-2", "previous-3"} {
+		if rules := violatedRules(t, policy.Validate(reused, u)); !slices.Equal(rules, []string{user.PasswordReused}) {
+			t.Fatalf("expected %s to be reused, got %v", reused, rules)
+		}
+	}
+	if err := policy.Validate("previous-4", u); err != nil {
+		t.Fatalf("expected a password older than the history to pass, got %v", err)
+	}
+
+	u.Roles = []primitive.ObjectID{service.ID}
+	if err := policy.Validate("current-1", u); err != nil {
+		t.Fatalf("expected an exempt role to reuse passwords, got %v", err)
+	}
+
+	u.Roles = nil
+	u.PasswordHistory = rememberPassword(u, policy.HistorySize())
+	u.Password = testHash(t, "next-0")
+	if len(u.PasswordHistory) != 2 {
+		t.Fatalf("expected the history to keep two passwords, got %d", len(u.PasswordHistory))
+	}
+	if rules := violatedRules(t, policy.Validate("current-1", u)); !slices.Equal(rules, []string{user.PasswordReused}) {
+		t.Fatalf("expected the replaced password to be remembered, got %v", rules)
+	}
+}
+
+func TestPasswordPolicyChangeReason(t *testing.T) {
+	backOffice := &role.Role{ID: primitive.NewObjectID(), Name: "back-office"}
+	policy := NewPasswordPolicy(config.PasswordPolicyConfig{
+		MaxAge:      90 * 24 * time.Hour,
+		MaxAgeRoles: []string{"back-office"},
+	}, nil, testHasher, &memoryRoles{roles: []*role.Role{backOffice}})
+
+	old := time.Now().UTC().AddDate(0, 0, -100)
+	u := &user.User{ID: primitive.NewObjectID(), CreatedAt: old}
+	if reason := policy.ChangeReason(u); reason != "" {
+		t.Fatalf("expected only back-office passwords to expire, got %q", reason)
+	}
+
+	u.Roles = []primitive.ObjectID{backOffice.ID}
+	if reason := policy.ChangeReason(u); reason != user.PasswordExpired {
+		t.Fatalf("expected a password as old as the user to be expired, got %q", reason)
+	}
+	u.PasswordChangedAt = time.Now().UTC().AddDate(0, 0, -10)
+	if reason := policy.ChangeReason(u); reason != "" {
+		t.Fatalf("expected a recent password to be fine, got %q", reason)
+	}
+
+	u.MustChangePassword = true
+	if reason := policy.ChangeReason(u); reason != user.PasswordChangeRequired {
+		t.Fatalf("expected the admin's flag to win, got %q", reason)
+	}
+}
@@ -1,11 +1,29 @@
 package user_token_store
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
 	"github.com/yasinsaee/go-user-service/pkg/redis"
 )
 
+// rotateScript checks that the old token is still current and replaces it, all at once, so
+// two refreshes with the same token cannot both pass. KEYS are the old and new token, the
+// user's token index and the family when there is one. ARGV are the old and new token and
+// the ttl in milliseconds.
+var rotateScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then return 0 end
+if KEYS[4] and redis.call('GET', KEYS[4]) ~= ARGV[1] then return 0 end
+redis.call('DEL', KEYS[1])
+redis.call('SREM', KEYS[3], ARGV[1])
+redis.call('SET', KEYS[2], ARGV[2], 'PX', ARGV[3])
+redis.call('SADD', KEYS[3], ARGV[2])
+redis.call('PEXPIRE', KEYS[3], ARGV[3])
+if KEYS[4] then redis.call('SET', KEYS[4], ARGV[2], 'PX', ARGV[3]) end
+return 1
+`)
+
 type refreshTokenStoreImpl struct {
 	refreshTokenExpire int64
 }
@@ -17,10 +35,27 @@ func NewRefreshTokenStore(refreshTokenExpire int64) *refreshTokenStoreImpl {
 	}
 }
 
-func (s *refreshTokenStoreImpl) Set(userID, refreshToken string) error {
+func (s *refreshTokenStoreImpl) Set(userID, familyID, refreshToken string) error {
 	key := "refresh_token:" + userID + ":" + refreshToken
 	ttl := time.Duration(s.refreshTokenExpire) * 24 * time.Hour
-	return redis.Set(key, refreshToken, ttl)
+	if err := redis.Set(key, refreshToken, ttl); err != nil {
+		return err
+	}
+
+	// per-user index so every token of the user can be revoked at once
+	index := "refresh_tokens:" + userID
+	if err := redis.SAdd(index, refreshToken); err != nil {
+		return err
+	}
+	if err := redis.Expire(index, ttl); err != nil {
+		return err
+	}
+
+	// tokens issued before families were introduced have none
+	if familyID == "" {
+		return nil
+	}
+	return redis.Set("refresh_family:"+familyID, refreshToken, ttl)
 }
 
 func (s *refreshTokenStoreImpl) Exists(userID, refreshToken string) (bool, error) {
@@ -37,5 +72,143 @@ func (s *refreshTokenStoreImpl) Exists(userID, refreshToken string) (bool, error
 
 func (s *refreshTokenStoreImpl) Delete(userID, refreshToken string) error {
 	key := "refresh_token:" + userID + ":" + refreshToken
+	if err := redis.Remove(key); err != nil {
+		return err
+	}
+	return redis.SRem("refresh_tokens:"+userID, refreshToken)
+}
+
+func (s *refreshTokenStoreImpl) Rotate(userID, familyID, refreshToken, newRefreshToken string) (bool, error) {
+	keys := []string{
+		"refresh_token:" + userID + ":" + refreshToken,
+		"refresh_token:" + userID + ":" + newRefreshToken,
+		"refresh_tokens:" + userID,
+	}
+	// tokens issued before families were introduced have none
+	if familyID != "" {
+		keys = append(keys, "refresh_family:"+familyID)
+	}
+
+	ttl := time.Duration(s.refreshTokenExpire) * 24 * time.Hour
+	rotated, err := redis.RunScript(rotateScript, keys, refreshToken, newRefreshToken, ttl.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	return rotated == int64(1), nil
+}
+
+func (s *refreshTokenStoreImpl) RevokeAll(userID string) error {
+	sessions, err := redis.SMembers("sessions:" + userID)
+	if err != nil {
+		return err
+	}
+	for _, familyID := range sessions {
+		if err := s.DeleteFamily(userID, familyID); err != nil {
+			return err
+		}
+	}
+
+	// tokens without a family are only reachable through the token index
+	index := "refresh_tokens:" + userID
+	tokens, err := redis.SMembers(index)
+	if err != nil {
+		return err
+	}
+	for _, refreshToken := range tokens {
+		if err := redis.Remove("refresh_token:" + userID + ":" + refreshToken); err != nil {
+			return err
+		}
+	}
+
+	if err := redis.Remove("sessions:" + userID); err != nil {
+		return err
+	}
+	return redis.Remove(index)
+}
+
+func (s *refreshTokenStoreImpl) FamilyExists(familyID string) (bool, error) {
+	return redis.Exists("refresh_family:" + familyID)
+}
+
+func (s *refreshTokenStoreImpl) DeleteFamily(userID, familyID string) error {
+	key := "refresh_family:" + familyID
+	current, err := redis.Get(key)
+	if err != nil && err != redis.ErrKeyNotFound {
+		return err
+	}
+	if err == nil {
+		if err := s.Delete(userID, current); err != nil {
+			return err
+		}
+	}
+
+	if err := redis.Remove("session:" + familyID); err != nil {
+		return err
+	}
+	if err := redis.SRem("sessions:"+userID, familyID); err != nil {
+		return err
+	}
 	return redis.Remove(key)
 }
+
+func (s *refreshTokenStoreImpl) SaveSession(session *user.Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Duration(s.refreshTokenExpire) * 24 * time.Hour
+	if err := redis.Set("session:"+session.ID, data, ttl); err != nil {
+		return err
+	}
+
+	index := "sessions:" + session.UserID
+	if err := redis.SAdd(index, session.ID); err != nil {
+		return err
+	}
+	// the index outlives every session in it, dangling members are dropped when listing
+	return redis.Expire(index, ttl)
+}
+
+func (s *refreshTokenStoreImpl) GetSession(userID, sessionID string) (*user.Session, error) {
+	data, err := redis.Get("session:" + sessionID)
+	if err == redis.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session := new(user.Session)
+	if err := json.Unmarshal([]byte(data), session); err != nil {
+		return nil, err
+	}
+	if session.UserID != userID {
+		return nil, nil
+	}
+	return session, nil
+}
+
+func (s *refreshTokenStoreImpl) ListSessions(userID string) (user.Sessions, error) {
+	index := "sessions:" + userID
+	ids, err := redis.SMembers(index)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make(user.Sessions, 0, len(ids))
+	for _, id := range ids {
+		session, err := s.GetSession(userID, id)
+		if err != nil {
+			return nil, err
+		}
+		if session == nil {
+			if err := redis.SRem(index, id); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, nil
+}
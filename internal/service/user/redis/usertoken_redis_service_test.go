@@ -0,0 +1,215 @@
+package user_token_store
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/pkg/redis"
+)
+
+func startRedis(t *testing.T) *miniredis.Miniredis {
+	mr := miniredis.RunT(t)
+	redis.Init(redis.Config{Addr: mr.Addr()})
+	return mr
+}
+
+func TestRotate(t *testing.T) {
+	startRedis(t)
+	s := NewRefreshTokenStore(30)
+
+	if err := s.Set("u1", "f1", "t1"); err != nil {
+		t.Fatal(err)
+	}
+	if rotated, err := s.Rotate("u1", "f1", "t1", "t2"); err != nil || !rotated {
+		t.Fatalf("expected the current token to rotate, got %v, %v", rotated, err)
+	}
+	if ok, _ := s.Exists("u1", "t1"); ok {
+		t.Fatal("expected the rotated token to be gone")
+	}
+	if ok, _ := s.Exists("u1", "t2"); !ok {
+		t.Fatal("expected the new token to be stored")
+	}
+	if current, _ := redis.Get("refresh_family:f1"); current != "t2" {
+		t.Fatalf("expected the new token to be current in its family, got %q", current)
+	}
+
+	// a token already rotated away must not rotate again
+	if rotated, err := s.Rotate("u1", "f1", "t1", "t3"); err != nil || rotated {
+		t.Fatalf("expected a rotated token to be refused, got %v, %v", rotated, err)
+	}
+	if ok, _ := s.Exists("u1", "t3"); ok {
+		t.Fatal("expected nothing to be stored for a refused rotation")
+	}
+}
+
+func TestRotateConcurrently(t *testing.T) {
+	startRedis(t)
+	s := NewRefreshTokenStore(30)
+	if err := s.Set("u1", "f1", "t1"); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]bool, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rotated, err := s.Rotate("u1", "f1", "t1", "next-"+string(rune('a'+i)))
+			if err != nil {
+				t.Error(err)
+			}
+			results[i] = rotated
+		}(i)
+	}
+	wg.Wait()
+
+	winners := 0
+	for _, rotated := range results {
+		if rotated {
+			winners++
+		}
+	}
+	if winners != 1 {
+		t.Fatalf("expected exactly one refresh to win, got %d", winners)
+	}
+	tokens, _ := redis.SMembers("refresh_tokens:u1")
+	if len(tokens) != 1 {
+		t.Fatalf("expected the family not to fork, got tokens %v", tokens)
+	}
+}
+
+func TestDeleteFamily(t *testing.T) {
+	startRedis(t)
+	s := NewRefreshTokenStore(30)
+
+	for _, f := range []string{"f1", "f2"} {
+		if err := s.Set("u1", f, f+"-token"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.DeleteFamily("u1", "f1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if alive, _ := s.FamilyExists("f1"); alive {
+		t.Fatal("expected the family to be revoked")
+	}
+	if ok, _ := s.Exists("u1", "f1-token"); ok {
+		t.Fatal("expected the current token of the family to be revoked with it")
+	}
+	if alive, _ := s.FamilyExists("f2"); !alive {
+		t.Fatal("expected other families to be left alone")
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	mr := startRedis(t)
+	s := NewRefreshTokenStore(30)
+
+	for _, session := range []user.Session{
+		{ID: "f1", UserID: "u1", DeviceName: "laptop"},
+		{ID: "f2", UserID: "u1", DeviceName: "phone"},
+		{ID: "f3", UserID: "u2"},
+	} {
+		if err := s.SaveSession(&session); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sessions, err := s.ListSessions("u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected the two sessions of the user, got %+v", sessions)
+	}
+
+	// an expired session leaves its id in the index
+	mr.Del("session:f2")
+	sessions, _ = s.ListSessions("u1")
+	if len(sessions) != 1 || sessions[0].DeviceName != "laptop" {
+		t.Fatalf("expected only the live session, got %+v", sessions)
+	}
+	if ids, _ := redis.SMembers("sessions:u1"); len(ids) != 1 {
+		t.Fatalf("expected the dangling id to be dropped from the index, got %v", ids)
+	}
+
+	if session, _ := s.GetSession("u1", "f3"); session != nil {
+		t.Fatal("expected the session of another user not to be found")
+	}
+}
+
+func TestDeleteFamilyEndsSession(t *testing.T) {
+	startRedis(t)
+	s := NewRefreshTokenStore(30)
+
+	if err := s.Set("u1", "f1", "t1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveSession(&user.Session{ID: "f1", UserID: "u1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteFamily("u1", "f1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if session, _ := s.GetSession("u1", "f1"); session != nil {
+		t.Fatal("expected the session to end with its family")
+	}
+	if sessions, _ := s.ListSessions("u1"); len(sessions) != 0 {
+		t.Fatalf("expected no sessions left, got %+v", sessions)
+	}
+}
+
+func TestRevokeAll(t *testing.T) {
+	startRedis(t)
+	s := NewRefreshTokenStore(30)
+
+	// tokens of older releases have no family, only the index knows them
+	for _, tok := range []struct{ user, family, token string }{
+		{"u1", "f1", "t1"},
+		{"u1", "f2", "t2"},
+		{"u1", "", "legacy"},
+		{"u2", "f3", "t3"},
+	} {
+		if err := s.Set(tok.user, tok.family, tok.token); err != nil {
+			t.Fatal(err)
+		}
+		if tok.family != "" {
+			if err := s.SaveSession(&user.Session{ID: tok.family, UserID: tok.user}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := s.RevokeAll("u1"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range []string{"t1", "t2", "legacy"} {
+		if ok, _ := s.Exists("u1", token); ok {
+			t.Fatalf("expected %s to be revoked", token)
+		}
+	}
+	for _, family := range []string{"f1", "f2"} {
+		if alive, _ := s.FamilyExists(family); alive {
+			t.Fatalf("expected family %s to be revoked", family)
+		}
+	}
+	if sessions, _ := s.ListSessions("u1"); len(sessions) != 0 {
+		t.Fatalf("expected no sessions left, got %+v", sessions)
+	}
+	if exists, _ := redis.Exists("refresh_tokens:u1"); exists {
+		t.Fatal("expected the token index to be dropped")
+	}
+
+	if ok, _ := s.Exists("u2", "t3"); !ok {
+		t.Fatal("expected other users to stay logged in")
+	}
+	if sessions, _ := s.ListSessions("u2"); len(sessions) != 1 {
+		t.Fatal("expected the sessions of other users to be left alone")
+	}
+}
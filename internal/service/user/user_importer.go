@@ -0,0 +1,197 @@
+package user
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/pkg/password"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maxImportLine is the longest JSON line accepted, far above any real user
+const maxImportLine = 1 << 20
+
+// userImporter is the concrete implementation of UserImporter.
+type userImporter struct {
+	repo user.UserRepository
+}
+
+// NewUserImporter returns a new instance of UserImporter.
+func NewUserImporter(repo user.UserRepository) user.UserImporter {
+	return &userImporter{
+		repo: repo,
+	}
+}
+
+func (i *userImporter) Import(r io.Reader, format string, dryRun bool) (*user.ImportResult, error) {
+	result := &user.ImportResult{}
+	seen := map[string]bool{} // the file may repeat a user, dry runs create nothing to find
+
+	importRecord := func(line int, rec *user.ImportRecord, err error) {
+		username := rec.Username
+		if err == nil {
+			username, err = i.importRecord(rec, seen, dryRun)
+		}
+		if err != nil {
+			result.Failures = append(result.Failures, user.ImportFailure{Line: line, Username: username, Reason: err.Error()})
+			return
+		}
+		result.Imported++
+	}
+
+	var err error
+	switch format {
+	case user.ImportJSONL:
+		err = readJSONL(r, importRecord)
+	case user.ImportCSV:
+		err = readCSV(r, importRecord)
+	default:
+		return nil, fmt.Errorf("unknown import format %q, use %s or %s", format, user.ImportJSONL, user.ImportCSV)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// importRecord creates the user of rec and returns its username.
+func (i *userImporter) importRecord(rec *user.ImportRecord, seen map[string]bool, dryRun bool) (string, error) {
+	username := rec.Username
+	if username == "" {
+		username = rec.PhoneNumber
+	}
+	if username == "" {
+		username = rec.Email
+	}
+	if username == "" {
+		return "", errors.New("username, phone_number or email is required")
+	}
+
+	if rec.PasswordHash == "" || rec.HashFormat == "" {
+		return username, errors.New("password_hash and hash_format are required")
+	}
+	if err := password.CheckLegacy(rec.HashFormat, rec.PasswordHash); err != nil {
+		return username, fmt.Errorf("invalid %s hash: %w", rec.HashFormat, err)
+	}
+
+	// FindByUsername matches usernames, phone numbers and emails alike
+	for _, key := range []string{username, rec.PhoneNumber, rec.Email} {
+		if key == "" {
+			continue
+		}
+		if seen[key] {
+			return username, fmt.Errorf("%s appears twice in the file", key)
+		}
+		_, err := i.repo.FindByUsername(key)
+		if err == nil {
+			return username, fmt.Errorf("%s already belongs to a user", key)
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return username, err
+		}
+	}
+	for _, key := range []string{username, rec.PhoneNumber, rec.Email} {
+		if key != "" {
+			seen[key] = true
+		}
+	}
+	if dryRun {
+		return username, nil
+	}
+
+	now := time.Now().UTC()
+	return username, i.repo.Create(&user.User{
+		Username:    username,
+		Email:       rec.Email,
+		PhoneNumber: rec.PhoneNumber,
+		FirstName:   rec.FirstName,
+		LastName:    rec.LastName,
+		IsActive:    true,
+		CreatedAt:   now,
+		LegacyPassword: &user.LegacyPassword{
+			Format:     rec.HashFormat,
+			Hash:       rec.PasswordHash,
+			Salt:       rec.Salt,
+			ImportedAt: now,
+		},
+	})
+}
+
+func readJSONL(r io.Reader, importRecord func(line int, rec *user.ImportRecord, err error)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxImportLine)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		rec := &user.ImportRecord{}
+		if err := json.Unmarshal([]byte(text), rec); err != nil {
+			importRecord(line, rec, fmt.Errorf("invalid JSON: %w", err))
+			continue
+		}
+		importRecord(line, rec, nil)
+	}
+	return scanner.Err()
+}
+
+func readCSV(r io.Reader, importRecord func(line int, rec *user.ImportRecord, err error)) error {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read the header line: %w", err)
+	}
+	for _, column := range header {
+		if _, ok := importColumns(&user.ImportRecord{})[strings.TrimSpace(column)]; !ok {
+			return fmt.Errorf("unknown column %q", column)
+		}
+	}
+
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		// a short or long line is skipped, everything else leaves the rest of the file unreadable
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && errors.Is(err, csv.ErrFieldCount) {
+			importRecord(parseErr.Line, &user.ImportRecord{}, errors.New("wrong number of columns"))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		rec := &user.ImportRecord{}
+		columns := importColumns(rec)
+		for i, column := range header {
+			*columns[strings.TrimSpace(column)] = values[i]
+		}
+		line, _ := reader.FieldPos(0)
+		importRecord(line, rec, nil)
+	}
+}
+
+// importColumns names the fields of rec like its json tags.
+func importColumns(rec *user.ImportRecord) map[string]*string {
+	return map[string]*string{
+		"username":      &rec.Username,
+		"email":         &rec.Email,
+		"phone_number":  &rec.PhoneNumber,
+		"first_name":    &rec.FirstName,
+		"last_name":     &rec.LastName,
+		"password_hash": &rec.PasswordHash,
+		"hash_format":   &rec.HashFormat,
+		"salt":          &rec.Salt,
+	}
+}
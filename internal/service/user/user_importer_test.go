@@ -0,0 +1,106 @@
+package user
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type memoryUsers struct {
+	user.UserRepository
+	users []*user.User
+}
+
+func (r *memoryUsers) Create(u *user.User) error {
+	u.ID = primitive.NewObjectID()
+	r.users = append(r.users, u)
+	return nil
+}
+
+func (r *memoryUsers) FindByUsername(username string) (*user.User, error) {
+	for _, u := range r.users {
+		if u.Username == username || u.PhoneNumber == username || u.Email == username {
+			return u, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *memoryUsers) FindByID(id any) (*user.User, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *memoryUsers) Update(u *user.User) error {
+	return nil
+}
+
+// md5 of "s4lt" followed by "correct horse"
+const importCSV = `username,email,password_hash,hash_format,salt
+jane,jane@example.com,23899c6974cd8c90f085a14355699a5a,md5_salted,s4lt
+john,jane@example.com,23899c6974cd8c90f085a14355699a5a,md5_salted,s4lt
+bob,,23899c6974cd8c90f085a14355699a5a,md4,
+`
+
+func TestImportUsers(t *testing.T) {
+	repo := &memoryUsers{}
+	importer := NewUserImporter(repo)
+
+	result, err := importer.Import(strings.NewReader(importCSV), user.ImportCSV, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 1 || len(result.Failures) != 2 || len(repo.users) != 0 {
+		t.Fatalf("expected a dry run to accept jane only and create nobody, got %+v", result)
+	}
+	if f := result.Failures[0]; f.Line != 3 || f.Username != "john" {
+		t.Fatalf("expected john's repeated email to fail on line 3, got %+v", f)
+	}
+
+	if _, err := importer.Import(strings.NewReader(importCSV), user.ImportCSV, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.users) != 1 || repo.users[0].LegacyPassword == nil {
+		t.Fatalf("expected jane to be created with her legacy hash, got %+v", repo.users)
+	}
+
+	result, err = importer.Import(strings.NewReader(`{"username":"jim"`), user.ImportJSONL, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Failures) != 1 || !strings.HasPrefix(result.Failures[0].Reason, "invalid JSON") {
+		t.Fatalf("expected the broken line to be reported, got %+v", result)
+	}
+	if _, err := importer.Import(strings.NewReader(importCSV), "xml", false); err == nil {
+		t.Fatal("expected an unknown format to be refused")
+	}
+}
+
+func TestLoginUpgradesLegacyPassword(t *testing.T) {
+	repo := &memoryUsers{}
+	if _, err := NewUserImporter(repo).Import(strings.NewReader(importCSV), user.ImportCSV, false); err != nil {
+		t.Fatal(err)
+	}
+	s := &userService{repo: repo, hasher: testHasher}
+
+	if _, err := s.Login("jane", "wrong"); err == nil {
+		t.Fatal("expected a wrong password to fail")
+	}
+	u, err := s.Login("jane", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.LegacyPassword != nil || !strings.HasPrefix(u.Password, "$argon2id$") {
+		t.Fatalf("expected the legacy hash to be replaced, got %q", u.Password)
+	}
+	if _, err := s.Login("jane", "correct horse"); err != nil {
+		t.Fatalf("expected the native hash to verify, got %v", err)
+	}
+}
@@ -4,52 +4,171 @@ import (
 	"errors"
 	"time"
 
+	"github.com/yasinsaee/go-user-service/internal/domain/apikey"
+	"github.com/yasinsaee/go-user-service/internal/domain/mfa"
+	"github.com/yasinsaee/go-user-service/internal/domain/security"
+	"github.com/yasinsaee/go-user-service/internal/domain/token"
 	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	"github.com/yasinsaee/go-user-service/pkg/logger"
+	"github.com/yasinsaee/go-user-service/pkg/password"
 	"github.com/yasinsaee/go-user-service/pkg/util"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type userService struct {
 	repo       user.UserRepository
 	tokenStore user.RefreshTokenStore // Redis-based limiter
+	devices    mfa.TrustedDeviceStore
+	events     security.EventPublisher
+	tokens     token.TokenService // revokes issued access tokens
+	passwords  user.PasswordPolicy
+	hasher     user.PasswordHasher
+	apiKeys    apikey.ApiKeyRepository
 }
 
 // NewUserService returns a new instance of UserService.
-func NewUserService(repo user.UserRepository, tokenStore user.RefreshTokenStore) user.UserService {
+func NewUserService(repo user.UserRepository, tokenStore user.RefreshTokenStore, devices mfa.TrustedDeviceStore, events security.EventPublisher, tokens token.TokenService, passwords user.PasswordPolicy, hasher user.PasswordHasher, apiKeys apikey.ApiKeyRepository) user.UserService {
 	return &userService{
 		repo:       repo,
 		tokenStore: tokenStore,
+		devices:    devices,
+		events:     events,
+		tokens:     tokens,
+		passwords:  passwords,
+		hasher:     hasher,
+		apiKeys:    apiKeys,
 	}
 }
 
 func (s *userService) Register(username string, user *user.User) error {
+	if err := s.passwords.Validate(user.Password, user); err != nil {
+		return err
+	}
+	return s.create(username, user)
+}
+
+// create stores a new user without checking the password against the policy.
+func (s *userService) create(username string, user *user.User) error {
 	_, err := s.repo.FindByUsername(username)
 	if err == nil {
 		return errors.New("username already exists")
 	}
 
-	hashed := util.HashPassword(user.Password)
+	hashed, err := s.hasher.Hash(user.Password)
+	if err != nil {
+		return err
+	}
 
 	user.Password = hashed
+	user.IsActive = true
 	user.CreatedAt = time.Now().UTC()
+	user.PasswordChangedAt = user.CreatedAt
 	return s.repo.Create(user)
 }
 
 func (s *userService) Login(username, password string) (*user.User, error) {
-	user, err := s.repo.FindByUsername(username)
-	if err != nil || user == nil {
+	u, err := s.repo.FindByUsername(username)
+	if err != nil || u == nil {
 		return nil, errors.New("invalid username or password")
 	}
 
-	if !util.CheckPasswordHash(password, user.Password) {
+	ok, rehash := s.checkPassword(u, password)
+	if !ok {
 		return nil, errors.New("invalid username or password")
 	}
+	if !u.Active() {
+		return nil, user.ErrDeactivated
+	}
 
-	user.LastLogin = time.Now().UTC()
-	if err = s.Update(user); err != nil {
+	// the password is known right now, the only chance to move it to the current hash
+	if rehash {
+		if hashed, err := s.hasher.Hash(password); err == nil {
+			u.Password = hashed
+			u.LegacyPassword = nil
+		} else {
+			logger.Warn("password rehash failed: ", err.Error())
+		}
+	}
+	u.LastLogin = time.Now().UTC()
+	if err = s.Update(u); err != nil {
 		return nil, errors.New("update failed")
 	}
 
-	return user, nil
+	return u, nil
+}
+
+func (s *userService) PasswordChangeReason(user *user.User) string {
+	return s.passwords.ChangeReason(user)
+}
+
+func (s *userService) RequirePasswordChange(user *user.User) error {
+	user.MustChangePassword = true
+	return s.Update(user)
+}
+
+// rememberPassword returns the history of u with its current password added, keeping
+// the historySize-1 newest ones, which with the next password are historySize.
+func rememberPassword(u *user.User, historySize int) []string {
+	// an imported user's hash is in a foreign format and not worth keeping
+	if historySize <= 1 || u.Password == "" || u.LegacyPassword != nil {
+		return u.PasswordHistory
+	}
+	history := append([]string{u.Password}, u.PasswordHistory...)
+	return history[:min(len(history), historySize-1)]
+}
+
+// checkPassword verifies plain against the user's hash, or the legacy hash of an imported
+// user. rehash tells that the hash should be replaced by a current one.
+func (s *userService) checkPassword(u *user.User, plain string) (ok, rehash bool) {
+	if u.LegacyPassword != nil {
+		return password.VerifyLegacy(u.LegacyPassword.Format, plain, u.LegacyPassword.Hash, u.LegacyPassword.Salt), true
+	}
+	return s.hasher.Verify(plain, u.Password)
+}
+
+func (s *userService) VerifyPassword(user *user.User, password string) error {
+	if ok, _ := s.checkPassword(user, password); !ok {
+		return errors.New("invalid password")
+	}
+	return nil
+}
+
+func (s *userService) LoginWithPhone(phoneNumber string, newUser *user.User) (*user.User, error) {
+	if phoneNumber == "" {
+		return nil, errors.New("phone number is required")
+	}
+
+	u, err := s.repo.FindByPhoneNumber(phoneNumber)
+	if err == nil {
+		if !u.Active() {
+			return nil, user.ErrDeactivated
+		}
+		u.LastLogin = time.Now().UTC()
+		if err = s.Update(u); err != nil {
+			return nil, errors.New("update failed")
+		}
+		return u, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	if newUser == nil {
+		return nil, errors.New("no user with this phone number")
+	}
+
+	newUser.Username = phoneNumber
+	newUser.PhoneNumber = phoneNumber
+	// nobody knows it, the user logs in with codes until a password is set
+	password, err := util.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	newUser.Password = password
+	newUser.LastLogin = time.Now().UTC()
+	if err := s.create(phoneNumber, newUser); err != nil {
+		return nil, err
+	}
+	return newUser, nil
 }
 
 func (s *userService) GetByID(id any) (*user.User, error) {
@@ -65,6 +184,30 @@ func (s *userService) Update(user *user.User) error {
 	return s.repo.Update(user)
 }
 
+func (s *userService) SetActive(user *user.User, active bool) error {
+	if active == user.Active() {
+		return nil
+	}
+
+	user.IsActive = active
+	user.DeactivatedAt = time.Time{}
+	if !active {
+		user.DeactivatedAt = time.Now().UTC()
+	}
+	if err := s.Update(user); err != nil {
+		return err
+	}
+	if active {
+		return nil
+	}
+
+	// a deactivated user must not keep using the tokens and keys it already has
+	if err := s.RevokeAllTokens(user.ID.Hex()); err != nil {
+		return err
+	}
+	return s.apiKeys.DeleteByUserID(user.ID)
+}
+
 func (s *userService) Delete(id any) error {
 	return s.repo.Delete(id)
 }
@@ -74,7 +217,7 @@ func (s *userService) ListAll() (user.Users, error) {
 }
 
 func (s *userService) ResetPassword(user *user.User, currentPassword, password, rePassword string) error {
-	if !util.CheckPasswordHash(currentPassword, user.Password) {
+	if ok, _ := s.checkPassword(user, currentPassword); !ok {
 		return errors.New("password_is_not_ok")
 	}
 	if err := s.UpdatePassword(user, password, rePassword); err != nil {
@@ -87,12 +230,32 @@ func (s *userService) UpdatePassword(user *user.User, password, rePassword strin
 	if password != rePassword {
 		return errors.New("password_is_not_matched")
 	}
-	user.Password = util.HashPassword(password)
-	return s.Update(user)
+	if err := s.passwords.Validate(password, user); err != nil {
+		return err
+	}
+	hashed, err := s.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+	user.PasswordHistory = rememberPassword(user, s.passwords.HistorySize())
+	user.Password = hashed
+	user.PasswordChangedAt = time.Now().UTC()
+	user.MustChangePassword = false
+	user.LegacyPassword = nil
+	if err := s.Update(user); err != nil {
+		return err
+	}
+
+	// whoever knew the old password may hold tokens, so every device has to login again
+	if err := s.RevokeAllTokens(user.ID.Hex()); err != nil {
+		return err
+	}
+	// and pass the second factor again
+	return s.devices.DeleteAll(user.ID.Hex())
 }
 
-func (s *userService) StoreRefreshToken(userID string, refreshToken string) error {
-	return s.tokenStore.Set(userID, refreshToken)
+func (s *userService) StoreRefreshToken(userID string, familyID string, refreshToken string) error {
+	return s.tokenStore.Set(userID, familyID, refreshToken)
 }
 
 func (s *userService) ValidateRefreshToken(userID string, refreshToken string) (bool, error) {
@@ -102,3 +265,106 @@ func (s *userService) ValidateRefreshToken(userID string, refreshToken string) (
 func (s *userService) RevokeRefreshToken(userID string, refreshToken string) error {
 	return s.tokenStore.Delete(userID, refreshToken)
 }
+
+// RevokeAllTokens logs the user out everywhere, dropping every refresh token
+// and revoking every access token issued until now. API keys are left alone, the
+// integrations using them are no sessions of the user.
+func (s *userService) RevokeAllTokens(userID string) error {
+	if err := s.tokenStore.RevokeAll(userID); err != nil {
+		return err
+	}
+	return s.tokens.RevokeUserTokens(userID, time.Now().UTC())
+}
+
+func (s *userService) RotateRefreshToken(userID string, familyID string, refreshToken string, newRefreshToken string) (bool, error) {
+	return s.tokenStore.Rotate(userID, familyID, refreshToken, newRefreshToken)
+}
+
+func (s *userService) RevokeRefreshTokenFamily(userID string, familyID string) error {
+	return s.tokenStore.DeleteFamily(userID, familyID)
+}
+
+func (s *userService) DetectRefreshTokenReuse(userID string, familyID string, tokenID string) (bool, error) {
+	if familyID == "" {
+		return false, nil
+	}
+
+	alive, err := s.tokenStore.FamilyExists(familyID)
+	if err != nil || !alive {
+		return false, err
+	}
+
+	if err := s.tokenStore.DeleteFamily(userID, familyID); err != nil {
+		return false, err
+	}
+
+	event := security.Event{
+		Type:   security.EventRefreshTokenReuse,
+		UserID: userID,
+		Details: map[string]string{
+			"family_id": familyID,
+			"token_id":  tokenID,
+		},
+		OccurredAt: time.Now().UTC(),
+	}
+	if err := s.events.Publish(event); err != nil {
+		// the family is already revoked, a lost event must not let the token through
+		logger.Error("failed to publish security event: ", err.Error())
+	}
+
+	return true, nil
+}
+
+// RecordSession creates the session on login and refreshes it on every token rotation.
+func (s *userService) RecordSession(session *user.Session) error {
+	now := time.Now().UTC()
+	session.CreatedAt = now
+	session.LastRefresh = now
+
+	existing, err := s.tokenStore.GetSession(session.UserID, session.ID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		session.CreatedAt = existing.CreatedAt
+		if session.DeviceName == "" {
+			session.DeviceName = existing.DeviceName
+		}
+	}
+
+	return s.tokenStore.SaveSession(session)
+}
+
+func (s *userService) ListSessions(userID string) (user.Sessions, error) {
+	return s.tokenStore.ListSessions(userID)
+}
+
+func (s *userService) RevokeSession(userID string, sessionID string) error {
+	session, err := s.tokenStore.GetSession(userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return errors.New("session_not_found")
+	}
+	return s.tokenStore.DeleteFamily(userID, sessionID)
+}
+
+func (s *userService) RevokeAllOtherSessions(userID string, currentSessionID string) ([]string, error) {
+	sessions, err := s.tokenStore.ListSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var revoked []string
+	for _, session := range sessions {
+		if session.ID == currentSessionID {
+			continue
+		}
+		if err := s.tokenStore.DeleteFamily(userID, session.ID); err != nil {
+			return revoked, err
+		}
+		revoked = append(revoked, session.ID)
+	}
+	return revoked, nil
+}
@@ -0,0 +1,235 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/yasinsaee/go-user-service/internal/domain/apikey"
+	"github.com/yasinsaee/go-user-service/internal/domain/security"
+	"github.com/yasinsaee/go-user-service/internal/domain/token"
+	"github.com/yasinsaee/go-user-service/internal/domain/user"
+	user_token_store "github.com/yasinsaee/go-user-service/internal/service/user/redis"
+	"github.com/yasinsaee/go-user-service/pkg/redis"
+	"github.com/yasinsaee/go-user-service/pkg/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryApiKeys struct {
+	apikey.ApiKeyRepository
+	keys apikey.ApiKeys
+}
+
+func (r *memoryApiKeys) DeleteByUserID(userID any) error {
+	objID, err := util.ToObjectID(userID)
+	if err != nil {
+		return err
+	}
+	kept := r.keys[:0]
+	for _, k := range r.keys {
+		if k.UserID != objID {
+			kept = append(kept, k)
+		}
+	}
+	r.keys = kept
+	return nil
+}
+
+type memoryRevocations struct {
+	token.TokenService
+	before map[string]time.Time
+}
+
+func (r *memoryRevocations) RevokeUserTokens(userID string, before time.Time) error {
+	r.before[userID] = before
+	return nil
+}
+
+type countingRefreshTokens struct {
+	user.RefreshTokenStore
+	revoked []string
+}
+
+func (s *countingRefreshTokens) RevokeAll(userID string) error {
+	s.revoked = append(s.revoked, userID)
+	return nil
+}
+
+func TestRevokeAllTokensKeepsApiKeys(t *testing.T) {
+	owner := primitive.NewObjectID()
+	keys := &memoryApiKeys{keys: apikey.ApiKeys{{UserID: owner}}}
+	revocations := &memoryRevocations{before: map[string]time.Time{}}
+	refreshTokens := &countingRefreshTokens{}
+	s := &userService{tokenStore: refreshTokens, tokens: revocations, apiKeys: keys}
+
+	if err := s.RevokeAllTokens(owner.Hex()); err != nil {
+		t.Fatal(err)
+	}
+	if len(refreshTokens.revoked) != 1 || revocations.before[owner.Hex()].IsZero() {
+		t.Fatal("expected the refresh and access tokens of the owner to be revoked")
+	}
+	if len(keys.keys) != 1 {
+		t.Fatal("expected the api keys to survive a logout everywhere")
+	}
+}
+
+func TestDeactivateDeletesApiKeys(t *testing.T) {
+	owner, other := &user.User{IsActive: true}, primitive.NewObjectID()
+	repo := &memoryUsers{}
+	if err := repo.Create(owner); err != nil {
+		t.Fatal(err)
+	}
+	keys := &memoryApiKeys{keys: apikey.ApiKeys{{UserID: owner.ID}, {UserID: other}, {UserID: owner.ID}}}
+	s := &userService{repo: repo, tokenStore: &countingRefreshTokens{}, tokens: &memoryRevocations{before: map[string]time.Time{}}, apiKeys: keys}
+
+	if err := s.SetActive(owner, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys.keys) != 1 || keys.keys[0].UserID != other {
+		t.Fatalf("expected only the keys of the owner to be deleted, got %+v", keys.keys)
+	}
+}
+
+type recordingEvents struct {
+	events []security.Event
+}
+
+func (p *recordingEvents) Publish(event security.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestDetectRefreshTokenReuseRevokesFamily(t *testing.T) {
+	mr := miniredis.RunT(t)
+	redis.Init(redis.Config{Addr: mr.Addr()})
+	events := &recordingEvents{}
+	s := &userService{tokenStore: user_token_store.NewRefreshTokenStore(30), events: events}
+
+	if err := s.StoreRefreshToken("u1", "f1", "t1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordSession(&user.Session{ID: "f1", UserID: "u1"}); err != nil {
+		t.Fatal(err)
+	}
+	if rotated, err := s.RotateRefreshToken("u1", "f1", "t1", "t2"); err != nil || !rotated {
+		t.Fatalf("expected the first refresh to rotate, got %v, %v", rotated, err)
+	}
+
+	// t1 comes back, whoever holds t2 may be the thief
+	if rotated, _ := s.RotateRefreshToken("u1", "f1", "t1", "t3"); rotated {
+		t.Fatal("expected a rotated token to be refused")
+	}
+	reused, err := s.DetectRefreshTokenReuse("u1", "f1", "jti-1")
+	if err != nil || !reused {
+		t.Fatalf("expected the reuse to be detected, got %v, %v", reused, err)
+	}
+
+	if ok, _ := s.ValidateRefreshToken("u1", "t2"); ok {
+		t.Fatal("expected the current token of the family to be revoked as well")
+	}
+	if sessions, _ := s.ListSessions("u1"); len(sessions) != 0 {
+		t.Fatalf("expected the session to end, got %+v", sessions)
+	}
+	if len(events.events) != 1 || events.events[0].Type != security.EventRefreshTokenReuse {
+		t.Fatalf("expected one reuse event, got %+v", events.events)
+	}
+
+	// the family is gone already, a second replay is a plain revoked token
+	if reused, _ := s.DetectRefreshTokenReuse("u1", "f1", "jti-1"); reused {
+		t.Fatal("expected a revoked family not to be reported again")
+	}
+}
+
+func TestRevokeSessions(t *testing.T) {
+	mr := miniredis.RunT(t)
+	redis.Init(redis.Config{Addr: mr.Addr()})
+	s := &userService{tokenStore: user_token_store.NewRefreshTokenStore(30)}
+
+	for _, id := range []string{"f1", "f2", "f3"} {
+		if err := s.StoreRefreshToken("u1", id, id+"-token"); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.RecordSession(&user.Session{ID: id, UserID: "u1", DeviceName: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a refresh keeps when the session started and the device it was named after
+	created, _ := s.tokenStore.GetSession("u1", "f1")
+	if err := s.RecordSession(&user.Session{ID: "f1", UserID: "u1"}); err != nil {
+		t.Fatal(err)
+	}
+	refreshed, _ := s.tokenStore.GetSession("u1", "f1")
+	if !refreshed.CreatedAt.Equal(created.CreatedAt) || refreshed.DeviceName != "f1" {
+		t.Fatalf("expected the session to keep its start and device, got %+v", refreshed)
+	}
+
+	if err := s.RevokeSession("u2", "f1"); err == nil {
+		t.Fatal("expected the session of another user not to be revoked")
+	}
+	if err := s.RevokeSession("u1", "f2"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := s.ValidateRefreshToken("u1", "f2-token"); ok {
+		t.Fatal("expected the refresh token of the revoked session to be gone")
+	}
+
+	revoked, err := s.RevokeAllOtherSessions("u1", "f1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revoked) != 1 || revoked[0] != "f3" {
+		t.Fatalf("expected only f3 to be revoked, got %v", revoked)
+	}
+	sessions, _ := s.ListSessions("u1")
+	if len(sessions) != 1 || sessions[0].ID != "f1" {
+		t.Fatalf("expected only the current session to be left, got %+v", sessions)
+	}
+}
+
+func TestDeactivateRevokesTokens(t *testing.T) {
+	repo := &memoryUsers{}
+	revocations := &memoryRevocations{before: map[string]time.Time{}}
+	refreshTokens := &countingRefreshTokens{}
+	s := &userService{repo: repo, tokenStore: refreshTokens, tokens: revocations, apiKeys: &memoryApiKeys{}, hasher: testHasher}
+
+	u := &user.User{Username: "jane", Password: "correct horse"}
+	if err := s.create(u.Username, u); err != nil {
+		t.Fatal(err)
+	}
+	if !u.Active() {
+		t.Fatal("expected a new user to be active")
+	}
+
+	if err := s.SetActive(u, false); err != nil {
+		t.Fatal(err)
+	}
+	if u.Active() {
+		t.Fatal("expected the user to be deactivated")
+	}
+	if len(refreshTokens.revoked) != 1 || revocations.before[u.ID.Hex()].IsZero() {
+		t.Fatal("expected the refresh and access tokens of the user to be revoked")
+	}
+	if _, err := s.Login("jane", "correct horse"); !errors.Is(err, user.ErrDeactivated) {
+		t.Fatalf("expected the login to be refused with ErrDeactivated, got %v", err)
+	}
+
+	if err := s.SetActive(u, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Login("jane", "correct horse"); err != nil {
+		t.Fatalf("expected an activated user to log in again, got %v", err)
+	}
+	if len(refreshTokens.revoked) != 1 {
+		t.Fatal("expected activating not to revoke anything")
+	}
+}
+
+func TestUsersFromBeforeDeactivationAreActive(t *testing.T) {
+	// stored with is_active false by releases that never set it
+	legacy := &user.User{}
+	if !legacy.Active() {
+		t.Fatal("expected a user that was never deactivated to be active")
+	}
+}
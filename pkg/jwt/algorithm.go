@@ -0,0 +1,157 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmES384 = "ES384"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var (
+	ErrInvalidKey           = errors.New("invalid key: key must be a PEM encoded RSA, ECDSA (P-256, P-384) or Ed25519 key")
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+
+	// supportedAlgorithms is the allow list handed to the parser, anything else (none, HS*) is rejected upfront
+	supportedAlgorithms = []string{AlgorithmRS256, AlgorithmES256, AlgorithmES384, AlgorithmEdDSA}
+)
+
+// parsePrivateKey decodes a PKCS#1, SEC 1 or PKCS#8 private key and detects its algorithm.
+func parsePrivateKey(data []byte) (crypto.PrivateKey, string, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, "", ErrInvalidKey
+	}
+
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, AlgorithmRS256, nil
+	case *ecdsa.PrivateKey:
+		alg, err := ecAlgorithm(k.Curve)
+		return k, alg, err
+	case ed25519.PrivateKey:
+		return k, AlgorithmEdDSA, nil
+	default:
+		return nil, "", ErrInvalidKey
+	}
+}
+
+// parsePublicKey decodes a PKIX or PKCS#1 public key and detects its algorithm.
+func parsePublicKey(data []byte) (crypto.PublicKey, string, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, "", ErrInvalidKey
+	}
+
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return k, AlgorithmRS256, nil
+	case *ecdsa.PublicKey:
+		alg, err := ecAlgorithm(k.Curve)
+		return k, alg, err
+	case ed25519.PublicKey:
+		return k, AlgorithmEdDSA, nil
+	default:
+		return nil, "", ErrInvalidKey
+	}
+}
+
+func ecAlgorithm(curve elliptic.Curve) (string, error) {
+	switch curve {
+	case elliptic.P256():
+		return AlgorithmES256, nil
+	case elliptic.P384():
+		return AlgorithmES384, nil
+	default:
+		return "", fmt.Errorf("%w: curve %s", ErrUnsupportedAlgorithm, curve.Params().Name)
+	}
+}
+
+// generateKeyPair creates a PEM encoded key pair for the given algorithm.
+func generateKeyPair(alg string) (privateKey []byte, publicKey []byte, err error) {
+	var (
+		key    crypto.PrivateKey
+		public crypto.PublicKey
+	)
+	switch alg {
+	case AlgorithmRS256, "":
+		k, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, nil, err
+		}
+		key, public = k, &k.PublicKey
+	case AlgorithmES256, AlgorithmES384:
+		curve := elliptic.P256()
+		if alg == AlgorithmES384 {
+			curve = elliptic.P384()
+		}
+		k, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		key, public = k, &k.PublicKey
+	case AlgorithmEdDSA:
+		pub, k, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		key, public = k, pub
+	default:
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, alg)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}),
+		nil
+}
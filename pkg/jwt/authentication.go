@@ -0,0 +1,86 @@
+package jwt
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Authentication methods of the amr claim (RFC 8176 where it has a name for them).
+const (
+	AMRPassword  = "pwd"
+	AMROTP       = "otp"      // one time code sent to the phone number
+	AMRTOTP      = "totp"     // authenticator app or recovery code
+	AMRWebAuthn  = "webauthn" // passkey with user verification
+	AMRFederated = "fed"      // upstream OpenID Connect provider
+)
+
+// Authentication context classes of the acr claim, after the NIST SP 800-63B assurance levels.
+const (
+	ACRSingleFactor = "aal1"
+	ACRMultiFactor  = "aal2"
+)
+
+// Authentication tells how and when the user proved who they are. It is set at login
+// and carried unchanged through every refresh of the session, only a step-up renews it.
+type Authentication struct {
+	AMR      []string `json:"amr,omitempty"`
+	ACR      string   `json:"acr,omitempty"`
+	AuthTime int64    `json:"auth_time,omitempty"`
+}
+
+// NewAuthentication records an authentication with methods that happened just now.
+func NewAuthentication(methods ...string) Authentication {
+	return Authentication{
+		AMR:      methods,
+		ACR:      acrOf(methods),
+		AuthTime: time.Now().UTC().Unix(),
+	}
+}
+
+// Add returns the authentication completed by a further factor, e.g. the second one of
+// a login. The time of the first factor is kept.
+func (a Authentication) Add(methods ...string) Authentication {
+	amr := slices.Clone(a.AMR)
+	for _, m := range methods {
+		if !slices.Contains(amr, m) {
+			amr = append(amr, m)
+		}
+	}
+	return Authentication{
+		AMR:      amr,
+		ACR:      acrOf(amr),
+		AuthTime: a.AuthTime,
+	}
+}
+
+// Age is the time since the authentication, tokens issued before it was recorded are infinitely old.
+func (a Authentication) Age() time.Duration {
+	if a.AuthTime == 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Since(time.Unix(a.AuthTime, 0))
+}
+
+// UsedAny reports whether one of methods was used.
+func (a Authentication) UsedAny(methods ...string) bool {
+	for _, m := range methods {
+		if slices.Contains(a.AMR, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// acrOf rates the methods: two of them, or a passkey which verifies the user on the
+// device holding it, are multi-factor.
+func acrOf(methods []string) string {
+	switch {
+	case len(methods) == 0:
+		return ""
+	case len(methods) > 1 || slices.Contains(methods, AMRWebAuthn):
+		return ACRMultiFactor
+	default:
+		return ACRSingleFactor
+	}
+}
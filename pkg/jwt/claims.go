@@ -0,0 +1,146 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+var (
+	ErrTokenNotValidYet   = errors.New("token is not valid yet")
+	ErrTokenUsedBeforeIat = errors.New("token used before issued")
+	ErrInvalidIssuer      = errors.New("token has invalid issuer")
+	ErrInvalidAudience    = errors.New("token has invalid audience")
+	ErrMissingClaim       = errors.New("token is missing a required claim")
+)
+
+type (
+	// Audience is the aud claim. It is a single string on the wire when there is
+	// one audience and an array otherwise, both forms are accepted when parsing.
+	Audience []string
+
+	// RegisteredClaims are the RFC 7519 claims carried by every token.
+	RegisteredClaims struct {
+		Issuer    string   `json:"iss,omitempty"`
+		Subject   string   `json:"sub,omitempty"`
+		Audience  Audience `json:"aud,omitempty"`
+		ExpiresAt int64    `json:"exp,omitempty"`
+		NotBefore int64    `json:"nbf,omitempty"`
+		IssuedAt  int64    `json:"iat,omitempty"`
+		TokenID   string   `json:"jti,omitempty"`
+		SessionID string   `json:"sid,omitempty"` // login session the token belongs to, see TokenConfig.FamilyID
+	}
+)
+
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// Contains reports whether any of the given audiences is in the claim.
+func (a Audience) Contains(audiences ...string) bool {
+	for _, want := range audiences {
+		for _, got := range a {
+			if got == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newRegisteredClaims fills the registered claims of a token issued now for subject.
+func newRegisteredClaims(subject string, exp time.Time) RegisteredClaims {
+	now := time.Now().UTC()
+	return RegisteredClaims{
+		Issuer:    conf.Issuer,
+		Subject:   subject,
+		Audience:  conf.Audience,
+		ExpiresAt: exp.Unix(),
+		NotBefore: now.Unix(),
+		IssuedAt:  now.Unix(),
+		TokenID:   newTokenID(),
+	}
+}
+
+// newTokenID returns a random 128 bit jti.
+func newTokenID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// isLegacy reports whether the token was issued before the registered claims were,
+// it then carries exp and nothing else of them.
+func (c RegisteredClaims) isLegacy() bool {
+	return c.ExpiresAt != 0 && c.IssuedAt == 0 && c.Subject == "" && c.TokenID == "" &&
+		c.Issuer == "" && len(c.Audience) == 0
+}
+
+// Valid implements jwt.Claims. Time based claims are compared with the
+// configured leeway to absorb clock skew between services. Legacy tokens
+// are only checked for expiry, and only while AcceptLegacyTokens is set.
+func (c RegisteredClaims) Valid() error {
+	now := time.Now().UTC().Unix()
+	leeway := int64(conf.Leeway / time.Second)
+
+	if c.isLegacy() && conf.AcceptLegacyTokens {
+		if now > c.ExpiresAt+leeway {
+			return ErrTokenExpired
+		}
+		return nil
+	}
+
+	if c.ExpiresAt == 0 || c.IssuedAt == 0 || c.Subject == "" || c.TokenID == "" {
+		return ErrMissingClaim
+	}
+	if now > c.ExpiresAt+leeway {
+		return ErrTokenExpired
+	}
+	if c.NotBefore != 0 && now+leeway < c.NotBefore {
+		return ErrTokenNotValidYet
+	}
+	if now+leeway < c.IssuedAt {
+		return ErrTokenUsedBeforeIat
+	}
+	if conf.Issuer != "" && c.Issuer != conf.Issuer {
+		return ErrInvalidIssuer
+	}
+	if len(conf.Audience) > 0 && !c.Audience.Contains(conf.Audience...) {
+		return ErrInvalidAudience
+	}
+	return nil
+}
+
+// parseClaims verifies the signature and registered claims of token, returning
+// our own error values instead of the parser's wrapped ones.
+func parseClaims(token string, claims jwt.Claims) error {
+	_, err := parser.ParseWithClaims(token, claims, verificationKey)
+	if ve, ok := err.(*jwt.ValidationError); ok && ve.Inner != nil {
+		return ve.Inner
+	}
+	return err
+}
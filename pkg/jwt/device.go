@@ -0,0 +1,44 @@
+package jwt
+
+import "time"
+
+// TokenTypeDevice marks a device the user trusts, it lets a login skip the second factor.
+const TokenTypeDevice TokenType = "device"
+
+// DeviceClaims is kept by a trusted device and shown at login. Its jti is the id of the
+// device record, the token is only honored while that record exists.
+type DeviceClaims struct {
+	ID   string    `json:"id"`
+	Type TokenType `json:"type"`
+	RegisteredClaims
+}
+
+// GenerateDeviceToken issues the token of a trusted device of the user, valid until exp.
+func GenerateDeviceToken(userID, deviceID string, exp time.Time) (string, time.Time, error) {
+	claims := &DeviceClaims{
+		ID:               userID,
+		Type:             TokenTypeDevice,
+		RegisteredClaims: newRegisteredClaims(userID, exp),
+	}
+	claims.TokenID = deviceID
+
+	return signToken(claims)
+}
+
+// ValidateDeviceToken verifies a token issued by GenerateDeviceToken.
+func ValidateDeviceToken(token string) (*DeviceClaims, error) {
+	claims := &DeviceClaims{}
+	if err := parseClaims(trimBearer(token), claims); err != nil {
+		return nil, err
+	}
+
+	if claims.Type != TokenTypeDevice {
+		return nil, ErrInvalidTokenType
+	}
+
+	if err := checkRevoked(claims.RegisteredClaims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,78 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileKeyStore keeps each signing key as a JSON document in a directory,
+// typically the mounted /app/keys volume.
+type fileKeyStore struct {
+	dir string
+}
+
+// NewFileKeyStore returns a KeyStore backed by the given directory.
+func NewFileKeyStore(dir string) KeyStore {
+	return &fileKeyStore{dir: dir}
+}
+
+func (s *fileKeyStore) path(kid string) string {
+	return filepath.Join(s.dir, kid+".json")
+}
+
+func (s *fileKeyStore) List() ([]*SigningKey, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*SigningKey, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		key := new(SigningKey)
+		if err := json.Unmarshal(data, key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (s *fileKeyStore) Save(key *SigningKey) error {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(key, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write then rename so a concurrent List never sees a partial file
+	tmp := s.path(key.KID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(key.KID))
+}
+
+func (s *fileKeyStore) Delete(kid string) error {
+	err := os.Remove(s.path(kid))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+package jwt
+
+import (
+	"slices"
+	"time"
+)
+
+type (
+	// UserInfo holds the standard claims about a user (OpenID Connect Core section 5.1),
+	// only the ones covered by the granted scopes are filled in.
+	UserInfo struct {
+		Name              string `json:"name,omitempty"`
+		GivenName         string `json:"given_name,omitempty"`
+		FamilyName        string `json:"family_name,omitempty"`
+		PreferredUsername string `json:"preferred_username,omitempty"`
+		Picture           string `json:"picture,omitempty"`
+		UpdatedAt         int64  `json:"updated_at,omitempty"`
+		Email             string `json:"email,omitempty"`
+		PhoneNumber       string `json:"phone_number,omitempty"`
+	}
+
+	// IDClaims is an OpenID Connect ID token. It tells a client who signed in and
+	// is addressed to that client, it is never accepted as an access token.
+	IDClaims struct {
+		Nonce           string `json:"nonce,omitempty"`
+		AuthorizedParty string `json:"azp,omitempty"`
+		UserInfo
+		Authentication
+		RegisteredClaims
+	}
+
+	// IDTokenConfig describes an ID token issued to a client for a user.
+	IDTokenConfig struct {
+		Subject        string
+		ClientID       string
+		SessionID      string
+		Nonce          string // echoed from the authorization request
+		Authentication Authentication
+		UserInfo       UserInfo
+	}
+)
+
+// GenerateIDToken issues an ID token for the client, it lives as long as an access token.
+func (t *IDTokenConfig) GenerateIDToken() (string, time.Time, error) {
+	exp := time.Now().UTC().Add(time.Hour * time.Duration(conf.AccessTokenExp))
+
+	claims := &IDClaims{
+		Nonce:            t.Nonce,
+		AuthorizedParty:  t.ClientID,
+		UserInfo:         t.UserInfo,
+		Authentication:   t.Authentication,
+		RegisteredClaims: newRegisteredClaims(t.Subject, exp),
+	}
+	claims.Audience = Audience{t.ClientID}
+	claims.SessionID = t.SessionID
+
+	return signToken(claims)
+}
+
+// Issuer returns the iss of issued tokens.
+func Issuer() string {
+	return conf.Issuer
+}
+
+// SigningAlgorithms returns the algorithms of the keys tokens may be signed with.
+func SigningAlgorithms() []string {
+	var algorithms []string
+	for _, k := range ring.published() {
+		if !slices.Contains(algorithms, k.Algorithm) {
+			algorithms = append(algorithms, k.Algorithm)
+		}
+	}
+	return algorithms
+}
@@ -0,0 +1,122 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+var ErrUnknownKeyID = errors.New("unknown key id")
+
+type (
+	// JSONWebKey is the public part of a signing key as described in RFC 7517.
+	JSONWebKey struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		N   string `json:"n,omitempty"`
+		E   string `json:"e,omitempty"`
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+		Y   string `json:"y,omitempty"`
+	}
+
+	// JSONWebKeySet is the document served to consumers that verify our tokens.
+	JSONWebKeySet struct {
+		Keys []JSONWebKey `json:"keys"`
+	}
+)
+
+// JWKS returns the verification keys of the service as a JSON Web Key Set.
+// Keys waiting to take over signing and retired keys that still verify tokens are included.
+func JWKS() (*JSONWebKeySet, error) {
+	set := &JSONWebKeySet{Keys: make([]JSONWebKey, 0)}
+	for _, k := range ring.published() {
+		publicKey, _, err := parsePublicKey(k.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		jwk, err := toJWK(publicKey, k.KID, k.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+// toJWK encodes the public key members of RFC 7518 section 6 and RFC 8037.
+func toJWK(key crypto.PublicKey, kid, alg string) (JSONWebKey, error) {
+	jwk := JSONWebKey{Kid: kid, Use: "sig", Alg: alg}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encodeBigInt(k.N)
+		jwk.E = encodeBigInt(big.NewInt(int64(k.E)))
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = k.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(k.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(k.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(k)
+	default:
+		return jwk, ErrInvalidKey
+	}
+	return jwk, nil
+}
+
+// keyID derives a stable key id from the RFC 7638 thumbprint of the public key.
+func keyID(key crypto.PublicKey) (string, error) {
+	jwk, err := toJWK(key, "", "")
+	if err != nil {
+		return "", err
+	}
+
+	// only the required members, in lexicographic order and without whitespace
+	var members any
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	thumbprint, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(thumbprint)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func encodeBigInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
@@ -1,7 +1,7 @@
 package jwt
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"errors"
 	"strings"
 	"time"
@@ -18,20 +18,36 @@ const (
 	TokenTypeRefresh TokenType = "refresh"
 )
 
+// PrincipalType tells who an access token was issued to.
+type PrincipalType string
+
+const (
+	PrincipalUser   PrincipalType = "user"
+	PrincipalClient PrincipalType = "client"  // a registered service using the client credentials grant
+	PrincipalAPIKey PrincipalType = "api_key" // a user's api key, never signed, resolved from the key on every request
+)
+
 var (
-	conf            JWTConfig
-	ErrTokenExpired = errors.New("token expired")
+	conf                JWTConfig
+	parser              = &jwt.Parser{ValidMethods: supportedAlgorithms}
+	ErrTokenExpired     = errors.New("token expired")
+	ErrAlgorithm        = errors.New("token algorithm does not match its key")
+	ErrInvalidTokenType = errors.New("invalid token type")
 )
 
 type (
 	// JWTClaims is a struct that will be encoded to a JWT.
 	JWTClaims struct {
-		ID       string    `json:"id"`
-		Username string    `json:"username"`
-		Roles    []string  `json:"roles"`
-		Access   []string  `json:"access"`
-		Type     TokenType `json:"type"`
-		jwt.StandardClaims
+		ID        string        `json:"id"`
+		Username  string        `json:"username"`
+		Roles     []string      `json:"roles"`
+		Access    []string      `json:"access"`
+		Type      TokenType     `json:"type"`
+		Principal PrincipalType `json:"principal,omitempty"` // empty on tokens issued before clients existed, which are user tokens
+		ClientID  string        `json:"client_id,omitempty"`
+		Scopes    []string      `json:"scope,omitempty"` // scopes granted to ClientID when it acts for a user
+		Authentication
+		RegisteredClaims
 	}
 
 	JWTConfig struct {
@@ -39,6 +55,22 @@ type (
 		PublicKey       []byte
 		AccessTokenExp  int
 		RefreshTokenExp int
+		ClientTokenExp  time.Duration // lifetime of client credentials tokens
+		DeviceTokenExp  time.Duration // lifetime of trusted device tokens
+
+		// KeyStore enables the key ring, without it PrivateKey/PublicKey are used as a single static key
+		KeyStore            KeyStore
+		RotationInterval    time.Duration // 0 disables automatic rotation
+		RotationGracePeriod time.Duration // delay between publishing a new key and signing with it
+		Algorithm           string        // algorithm of generated keys, imported keys keep the one detected from their PEM
+
+		Issuer   string        // iss of issued tokens, also required on parsed tokens when set
+		Audience []string      // aud of issued tokens, parsed tokens must name at least one of them when set
+		Leeway   time.Duration // tolerated clock skew for exp, nbf and iat
+
+		// AcceptLegacyTokens accepts tokens of releases before iss, aud, sub, iat and jti were
+		// issued until they expire, so upgrading does not log every user out
+		AcceptLegacyTokens bool
 	}
 
 	TokenConfig struct {
@@ -46,13 +78,32 @@ type (
 		Username string   `json:"username"`
 		Roles    []string `json:"roles"`
 		Access   []string `json:"access"`
+		FamilyID string   `json:"fid,omitempty"` // refresh token family and session id, a new one is started when empty
+
+		// set when the tokens are issued to an oauth client acting for the user,
+		// Access is then already narrowed down to the granted scopes
+		ClientID string   `json:"client_id,omitempty"`
+		Scopes   []string `json:"scope,omitempty"`
+
+		// how the user signed in to the session, copied into both tokens
+		Authentication Authentication `json:"authentication"`
+	}
+
+	// ClientTokenConfig describes an access token issued to a registered client.
+	ClientTokenConfig struct {
+		ClientID string
+		Scopes   []string // granted permissions, they end up in the Access claim
 	}
 
 	RefreshClaims struct {
 		ID       string    `json:"id"`
 		Username string    `json:"username"`
 		Type     TokenType `json:"type"`
-		jwt.StandardClaims
+		FamilyID string    `json:"fid,omitempty"`       // shared by every refresh token rotated from the same login
+		ClientID string    `json:"client_id,omitempty"` // oauth client the token was issued to, only it may refresh
+		Scopes   []string  `json:"scope,omitempty"`     // scopes granted to that client
+		Authentication
+		RegisteredClaims
 	}
 )
 
@@ -60,48 +111,101 @@ type Model interface {
 	Get(filter bson.M) error
 }
 
-func Init(config JWTConfig) {
+func Init(config JWTConfig) error {
 	conf = config
+
+	if conf.KeyStore == nil {
+		key, err := newSigningKey(conf.PrivateKey, conf.PublicKey, time.Time{})
+		if err != nil {
+			return err
+		}
+		ring.set([]*SigningKey{key})
+		return nil
+	}
+
+	if err := bootstrapKeys(); err != nil {
+		return err
+	}
+	return ReloadKeys()
 }
 
 func (t *TokenConfig) GenerateAccessToken() (string, time.Time, error) {
 	exp := time.Now().UTC().Add(time.Hour * time.Duration(conf.AccessTokenExp))
 
 	claims := &JWTClaims{
-		ID:       t.ID,
-		Username: t.Username,
-		Roles:    t.Roles,
-		Access:   t.Access,
-		Type:     TokenTypeAccess,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: exp.Unix(),
-		},
+		ID:               t.ID,
+		Username:         t.Username,
+		Roles:            t.Roles,
+		Access:           t.Access,
+		Type:             TokenTypeAccess,
+		Principal:        PrincipalUser,
+		ClientID:         t.ClientID,
+		Scopes:           t.Scopes,
+		Authentication:   t.Authentication,
+		RegisteredClaims: newRegisteredClaims(t.ID, exp),
+	}
+	claims.SessionID = t.family()
+
+	return signToken(claims)
+}
+
+// GenerateAccessToken issues a short lived access token for the client, it has no refresh token.
+func (t *ClientTokenConfig) GenerateAccessToken() (string, time.Time, error) {
+	exp := time.Now().UTC().Add(conf.ClientTokenExp)
+
+	claims := &JWTClaims{
+		Access:           t.Scopes,
+		Type:             TokenTypeAccess,
+		Principal:        PrincipalClient,
+		ClientID:         t.ClientID,
+		RegisteredClaims: newRegisteredClaims(t.ClientID, exp),
 	}
 
 	return signToken(claims)
 }
 
+// IsClient reports whether the token was issued to a registered client instead of a user.
+func (c *JWTClaims) IsClient() bool {
+	return c.Principal == PrincipalClient
+}
+
 func (t *TokenConfig) GenerateRefreshToken() (string, time.Time, error) {
-	exp := time.Now().UTC().Add(time.Hour * 24 * time.Duration(conf.RefreshTokenExp	))
+	exp := time.Now().UTC().Add(time.Hour * 24 * time.Duration(conf.RefreshTokenExp))
 	claims := &RefreshClaims{
-		ID:       t.ID,
-		Username: t.Username,
-		Type:     TokenTypeRefresh,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: exp.Unix(),
-		},
+		ID:               t.ID,
+		Username:         t.Username,
+		Type:             TokenTypeRefresh,
+		FamilyID:         t.family(),
+		ClientID:         t.ClientID,
+		Scopes:           t.Scopes,
+		Authentication:   t.Authentication,
+		RegisteredClaims: newRegisteredClaims(t.ID, exp),
 	}
 
 	return signToken(claims)
 }
 
+// family returns the family of the tokens being issued, starting a new one on first use.
+func (t *TokenConfig) family() string {
+	if t.FamilyID == "" {
+		t.FamilyID = newTokenID()
+	}
+	return t.FamilyID
+}
+
 func signToken(claims jwt.Claims) (string, time.Time, error) {
-	key, err := jwt.ParseRSAPrivateKeyFromPEM(conf.PrivateKey)
+	signingKey, err := ring.signing()
 	if err != nil {
 		return "", time.Time{}, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	key, alg, err := parsePrivateKey(signingKey.PrivateKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(alg), claims)
+	token.Header["kid"] = signingKey.KID
 	signed, err := token.SignedString(key)
 	if err != nil {
 		return "", time.Time{}, err
@@ -112,59 +216,19 @@ func signToken(claims jwt.Claims) (string, time.Time, error) {
 		return signed, time.Unix(c.ExpiresAt, 0), nil
 	case *RefreshClaims:
 		return signed, time.Unix(c.ExpiresAt, 0), nil
+	case *IDClaims:
+		return signed, time.Unix(c.ExpiresAt, 0), nil
+	case *MFAClaims:
+		return signed, time.Unix(c.ExpiresAt, 0), nil
+	case *DeviceClaims:
+		return signed, time.Unix(c.ExpiresAt, 0), nil
+	case *PasswordChangeClaims:
+		return signed, time.Unix(c.ExpiresAt, 0), nil
 	default:
 		return signed, time.Time{}, nil
 	}
 }
 
-func (t *TokenConfig) generateToken(expirationTime time.Time, privateKey []byte) (string, time.Time, error) {
-	claims := &JWTClaims{
-		ID:       t.ID,
-		Username: t.Username,
-		Roles:    t.Roles,
-		Access:   t.Access,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expirationTime.Unix(),
-		},
-	}
-
-	key, err := jwt.ParseRSAPrivateKeyFromPEM(privateKey)
-	if err != nil {
-		return "", time.Now().UTC(), err
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	tokenString, err := token.SignedString(key)
-	if err != nil {
-		return "", time.Now().UTC(), err
-	}
-
-	return tokenString, expirationTime, nil
-}
-
-func (t *TokenConfig) generateRefreshToken(expirationTime time.Time, privateKey []byte) (string, time.Time, error) {
-	claims := &RefreshClaims{
-		ID:       t.ID,
-		Username: t.Username,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expirationTime.Unix(),
-		},
-	}
-
-	key, err := jwt.ParseRSAPrivateKeyFromPEM(privateKey)
-	if err != nil {
-		return "", time.Now().UTC(), err
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	tokenString, err := token.SignedString(key)
-	if err != nil {
-		return "", time.Now().UTC(), err
-	}
-
-	return tokenString, expirationTime, nil
-}
-
 func CurrentToken(c *echo.Context) (*JWTClaims, error) {
 	token := (*c).Request().Header.Get("Authorization")
 	if token == "" {
@@ -173,57 +237,95 @@ func CurrentToken(c *echo.Context) (*JWTClaims, error) {
 	return validation(token)
 }
 
+// ValidateAccessToken verifies an access token against the key ring.
+func ValidateAccessToken(token string) (*JWTClaims, error) {
+	return validation(token)
+}
+
 func validation(token string) (*JWTClaims, error) {
-	token = strings.TrimPrefix(token, "Bearer ")
+	token = trimBearer(token)
 	claims := &JWTClaims{}
 
-	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(conf.PublicKey)
-	if err != nil {
+	if err := parseClaims(token, claims); err != nil {
 		return nil, err
 	}
 
-	_, err = jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
-		return publicKey, nil
-	})
-	if err != nil {
-		return nil, err
+	if claims.Type != TokenTypeAccess {
+		return nil, ErrInvalidTokenType
+	}
+	// legacy tokens name their user in id only
+	if claims.Subject == "" {
+		claims.Subject = claims.ID
 	}
 
-	if claims.ExpiresAt < time.Now().UTC().Unix() {
-		return nil, ErrTokenExpired
+	if err := checkRevoked(claims.RegisteredClaims); err != nil {
+		return nil, err
 	}
 
 	return claims, nil
 }
 
 func ValidateRefreshToken(token string) (*RefreshClaims, error) {
-	token = strings.TrimPrefix(token, "Bearer ")
+	token = trimBearer(token)
 
 	claims := &RefreshClaims{}
 
-	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(conf.PublicKey)
-	if err != nil {
-		return nil, err
-	}
-
-	_, err = jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
-		return publicKey, nil
-	})
-	if err != nil {
+	if err := parseClaims(token, claims); err != nil {
 		return nil, err
 	}
 
 	if claims.Type != TokenTypeRefresh {
-		return nil, errors.New("invalid token type")
+		return nil, ErrInvalidTokenType
+	}
+	if claims.Subject == "" {
+		claims.Subject = claims.ID
 	}
 
-	if claims.ExpiresAt < time.Now().UTC().Unix() {
-		return nil, ErrTokenExpired
+	if err := checkRevoked(claims.RegisteredClaims); err != nil {
+		return nil, err
 	}
 
 	return claims, nil
 }
 
-func GetPublicKey() (*rsa.PublicKey, error) {
-	return jwt.ParseRSAPublicKeyFromPEM(conf.PublicKey)
+func trimBearer(token string) string {
+	return strings.TrimPrefix(token, "Bearer ")
+}
+
+// GetPublicKey returns the public key of the current signing key.
+func GetPublicKey() (crypto.PublicKey, error) {
+	key, err := ring.signing()
+	if err != nil {
+		return nil, err
+	}
+	publicKey, _, err := parsePublicKey(key.PublicKey)
+	return publicKey, err
+}
+
+// verificationKey resolves the public key for a token by its kid header among
+// every key of the ring that has not expired yet. The token must use the
+// algorithm of that key, whatever else its header claims.
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	var key *SigningKey
+	if kid, ok := token.Header["kid"].(string); ok {
+		k, err := ring.find(kid)
+		if err != nil {
+			return nil, err
+		}
+		key = k
+	} else {
+		// tokens issued before kid was introduced were signed with the first key of the ring
+		keys := ring.published()
+		if len(keys) == 0 {
+			return nil, ErrNoSigningKey
+		}
+		key = keys[0]
+	}
+
+	if token.Method.Alg() != key.Algorithm {
+		return nil, ErrAlgorithm
+	}
+
+	publicKey, _, err := parsePublicKey(key.PublicKey)
+	return publicKey, err
 }
@@ -0,0 +1,547 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+func initTestKeys(t *testing.T) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Init(JWTConfig{
+		PrivateKey:      pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		PublicKey:       pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}),
+		AccessTokenExp:  1,
+		RefreshTokenExp: 1,
+	})
+}
+
+func TestTokenCarriesPublishedKeyID(t *testing.T) {
+	initTestKeys(t)
+
+	tc := TokenConfig{ID: "1", Username: "user"}
+	access, _, err := tc.GenerateAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, _, err := new(jwt.Parser).ParseUnverified(access, &JWTClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	set, err := JWKS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 1 {
+		t.Fatalf("JWKS() returned %d keys, want 1", len(set.Keys))
+	}
+	if got := parsed.Header["kid"]; got != set.Keys[0].Kid {
+		t.Errorf("token kid = %v, want %v", got, set.Keys[0].Kid)
+	}
+
+	if _, err := validation(access); err != nil {
+		t.Errorf("validation() error = %v", err)
+	}
+}
+
+func TestValidationRejectsUnknownKeyID(t *testing.T) {
+	initTestKeys(t)
+
+	tc := TokenConfig{ID: "1", Username: "user"}
+	refresh, _, err := tc.GenerateRefreshToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a new key pair changes the kid, so the old token must not resolve a key
+	initTestKeys(t)
+	if _, err := ValidateRefreshToken(refresh); err == nil {
+		t.Error("ValidateRefreshToken() accepted a token signed with an unknown key")
+	}
+}
+
+func TestRotationKeepsRetiredKeyForVerification(t *testing.T) {
+	if err := Init(JWTConfig{
+		AccessTokenExp:      1,
+		RefreshTokenExp:     1,
+		KeyStore:            NewFileKeyStore(t.TempDir()),
+		RotationGracePeriod: time.Hour,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tc := TokenConfig{ID: "1", Username: "user"}
+	before, _, err := tc.GenerateAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a graceful rotation only publishes the new key
+	pending, err := RotateKey(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current, _ := ring.signing(); current.KID == pending.KID {
+		t.Fatal("pending key signs before its grace period")
+	}
+	if set, _ := JWKS(); len(set.Keys) != 2 {
+		t.Fatalf("JWKS() returned %d keys, want 2", len(set.Keys))
+	}
+
+	forced, err := RotateKey(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, _, err := tc.GenerateAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _, _ := new(jwt.Parser).ParseUnverified(after, &JWTClaims{})
+	if parsed.Header["kid"] != forced.KID {
+		t.Errorf("token kid = %v, want %v", parsed.Header["kid"], forced.KID)
+	}
+
+	if _, err := validation(before); err != nil {
+		t.Errorf("token signed by the retired key rejected: %v", err)
+	}
+}
+
+func TestMaxTokenLifetimeCoversDeviceTokens(t *testing.T) {
+	defer func(c JWTConfig) { conf = c }(conf)
+	conf = JWTConfig{AccessTokenExp: 1, RefreshTokenExp: 7, ClientTokenExp: time.Minute, DeviceTokenExp: 30 * 24 * time.Hour}
+
+	// a retired key must keep verifying the device tokens it signed
+	if got := MaxTokenLifetime(); got != conf.DeviceTokenExp {
+		t.Errorf("MaxTokenLifetime() = %v, want the device token lifetime %v", got, conf.DeviceTokenExp)
+	}
+}
+
+func TestAlgorithmsDetectedFromPEM(t *testing.T) {
+	for _, alg := range []string{AlgorithmRS256, AlgorithmES256, AlgorithmES384, AlgorithmEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			privateKey, publicKey, err := generateKeyPair(alg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Init(JWTConfig{PrivateKey: privateKey, PublicKey: publicKey, AccessTokenExp: 1, RefreshTokenExp: 1}); err != nil {
+				t.Fatal(err)
+			}
+
+			tc := TokenConfig{ID: "1", Username: "user"}
+			access, _, err := tc.GenerateAccessToken()
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, _, _ := new(jwt.Parser).ParseUnverified(access, &JWTClaims{})
+			if parsed.Method.Alg() != alg {
+				t.Errorf("token alg = %v, want %v", parsed.Method.Alg(), alg)
+			}
+			if _, err := validation(access); err != nil {
+				t.Errorf("validation() error = %v", err)
+			}
+
+			set, err := JWKS()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if set.Keys[0].Alg != alg {
+				t.Errorf("JWKS alg = %v, want %v", set.Keys[0].Alg, alg)
+			}
+		})
+	}
+}
+
+func TestValidationPinsKeyAlgorithm(t *testing.T) {
+	initTestKeys(t)
+	signing, _ := ring.signing()
+
+	// classic confusion attack: HMAC signed with the published public key as secret
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTClaims{ID: "1", Type: TokenTypeAccess})
+	forged.Header["kid"] = signing.KID
+	token, err := forged.SignedString(signing.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := validation(token); err == nil {
+		t.Error("validation() accepted a token signed with a different algorithm than its key")
+	}
+}
+
+func TestRegisteredClaimsValidation(t *testing.T) {
+	initTestKeys(t)
+	conf.Issuer = "https://auth.example.com"
+	conf.Audience = []string{"shop", "billing"}
+	conf.Leeway = time.Minute
+
+	tc := TokenConfig{ID: "42", Username: "user"}
+	access, _, err := tc.GenerateAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := validation(access)
+	if err != nil {
+		t.Fatalf("validation() error = %v", err)
+	}
+	if claims.Subject != "42" || claims.TokenID == "" || claims.IssuedAt == 0 || claims.NotBefore == 0 {
+		t.Errorf("registered claims not set: %+v", claims.RegisteredClaims)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(c *RegisteredClaims)
+		want   error
+	}{
+		{"expired within leeway", func(c *RegisteredClaims) { c.ExpiresAt = time.Now().Add(-30 * time.Second).Unix() }, nil},
+		{"expired", func(c *RegisteredClaims) { c.ExpiresAt = time.Now().Add(-2 * time.Minute).Unix() }, ErrTokenExpired},
+		{"not valid yet", func(c *RegisteredClaims) { c.NotBefore = time.Now().Add(2 * time.Minute).Unix() }, ErrTokenNotValidYet},
+		{"other issuer", func(c *RegisteredClaims) { c.Issuer = "https://evil.example.com" }, ErrInvalidIssuer},
+		{"other audience", func(c *RegisteredClaims) { c.Audience = Audience{"crm"} }, ErrInvalidAudience},
+		{"single audience", func(c *RegisteredClaims) { c.Audience = Audience{"billing"} }, nil},
+		{"missing jti", func(c *RegisteredClaims) { c.TokenID = "" }, ErrMissingClaim},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := *claims
+			tt.mutate(&c.RegisteredClaims)
+			token, _, err := signToken(&c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := validation(token); err != tt.want {
+				t.Errorf("validation() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+type revokedIDs map[string]bool
+
+func (r revokedIDs) IsRevoked(claims RegisteredClaims) (bool, error) {
+	return r[claims.TokenID], nil
+}
+
+func TestLegacyTokensAcceptedUntilExpiry(t *testing.T) {
+	initTestKeys(t)
+	conf.Issuer = "https://auth.example.com"
+	conf.AcceptLegacyTokens = true
+
+	// what releases before the registered claims issued
+	legacy := func(exp time.Time) string {
+		token, _, err := signToken(&JWTClaims{ID: "42", Username: "user", Type: TokenTypeAccess, RegisteredClaims: RegisteredClaims{ExpiresAt: exp.Unix()}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	claims, err := ValidateAccessToken(legacy(time.Now().Add(time.Hour)))
+	if err != nil {
+		t.Fatalf("ValidateAccessToken(legacy) error = %v", err)
+	}
+	if claims.Subject != "42" {
+		t.Errorf("subject = %q, want the id of the legacy token", claims.Subject)
+	}
+	if _, err := ValidateAccessToken(legacy(time.Now().Add(-time.Hour))); err != ErrTokenExpired {
+		t.Errorf("ValidateAccessToken(expired legacy) error = %v, want %v", err, ErrTokenExpired)
+	}
+
+	conf.AcceptLegacyTokens = false
+	if _, err := ValidateAccessToken(legacy(time.Now().Add(time.Hour))); err != ErrMissingClaim {
+		t.Errorf("ValidateAccessToken(legacy) without the flag error = %v, want %v", err, ErrMissingClaim)
+	}
+}
+
+func TestValidationConsultsRevocationList(t *testing.T) {
+	initTestKeys(t)
+	revoked := revokedIDs{}
+	SetRevocationList(revoked)
+	defer SetRevocationList(nil)
+
+	tc := TokenConfig{ID: "1", Username: "user"}
+	access, _, err := tc.GenerateAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidateAccessToken(access); err != nil {
+		t.Fatalf("ValidateAccessToken() error = %v", err)
+	}
+
+	claims, err := ParseRegisteredClaims(access)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked[claims.TokenID] = true
+
+	if _, err := ValidateAccessToken(access); err != ErrTokenRevoked {
+		t.Errorf("ValidateAccessToken() error = %v, want %v", err, ErrTokenRevoked)
+	}
+}
+
+func TestRefreshTokensShareFamily(t *testing.T) {
+	initTestKeys(t)
+
+	login := TokenConfig{ID: "1", Username: "user"}
+	first, _, err := login.GenerateRefreshToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if login.FamilyID == "" {
+		t.Fatal("GenerateRefreshToken() did not start a family")
+	}
+
+	access, _, err := login.GenerateAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	accessClaims, err := ValidateAccessToken(access)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accessClaims.SessionID != login.FamilyID {
+		t.Errorf("sid = %q, want %q", accessClaims.SessionID, login.FamilyID)
+	}
+
+	rotation := TokenConfig{ID: "1", Username: "user", FamilyID: login.FamilyID}
+	second, _, err := rotation.GenerateRefreshToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range []string{first, second} {
+		claims, err := ValidateRefreshToken(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if claims.FamilyID != login.FamilyID {
+			t.Errorf("fid = %q, want %q", claims.FamilyID, login.FamilyID)
+		}
+	}
+
+	other := TokenConfig{ID: "1", Username: "user"}
+	if _, _, err := other.GenerateRefreshToken(); err != nil {
+		t.Fatal(err)
+	}
+	if other.FamilyID == login.FamilyID {
+		t.Error("a new login reused an existing family")
+	}
+}
+
+func TestClientTokenIsDistinguishable(t *testing.T) {
+	initTestKeys(t)
+	conf.ClientTokenExp = time.Minute
+
+	tc := ClientTokenConfig{ClientID: "billing", Scopes: []string{"user.read"}}
+	token, exp, err := tc.GenerateAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(exp) > time.Minute {
+		t.Errorf("expires in %v, want at most a minute", time.Until(exp))
+	}
+
+	claims, err := ValidateAccessToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !claims.IsClient() || claims.ClientID != "billing" || claims.Subject != "billing" {
+		t.Errorf("claims = %+v, want a client token for billing", claims)
+	}
+	if len(claims.Access) != 1 || claims.Access[0] != "user.read" {
+		t.Errorf("access = %v, want the granted scopes", claims.Access)
+	}
+
+	user := TokenConfig{ID: "1", Username: "user"}
+	userToken, _, err := user.GenerateAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims, err := ValidateAccessToken(userToken); err != nil || claims.IsClient() {
+		t.Errorf("user token IsClient() = true or error %v", err)
+	}
+}
+
+func TestIDTokenIsAddressedToClient(t *testing.T) {
+	initTestKeys(t)
+
+	tc := IDTokenConfig{
+		Subject:        "1",
+		ClientID:       "grafana",
+		Nonce:          "n-0S6_WzA2Mj",
+		Authentication: NewAuthentication(AMRPassword),
+		UserInfo:       UserInfo{Name: "Jane Doe", Email: "jane@example.com"},
+	}
+	token, _, err := tc.GenerateIDToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := &IDClaims{}
+	if err := parseClaims(token, claims); err != nil {
+		t.Fatal(err)
+	}
+	if !claims.Audience.Contains("grafana") || claims.AuthorizedParty != "grafana" || claims.Nonce != tc.Nonce {
+		t.Errorf("claims = %+v, want an ID token for grafana with the nonce", claims)
+	}
+	if claims.Subject != "1" || claims.Email != "jane@example.com" || claims.AuthTime == 0 {
+		t.Errorf("claims = %+v, want the user's claims", claims)
+	}
+
+	if _, err := ValidateAccessToken(token); err != ErrInvalidTokenType {
+		t.Errorf("ValidateAccessToken(id token) error = %v, want %v", err, ErrInvalidTokenType)
+	}
+}
+
+func TestMFATokenGrantsNothing(t *testing.T) {
+	initTestKeys(t)
+
+	token, exp, err := GenerateMFAToken("1", "user", NewAuthentication(AMRPassword))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(exp) > mfaTokenExp {
+		t.Errorf("expires in %v, want at most %v", time.Until(exp), mfaTokenExp)
+	}
+
+	claims, err := ValidateMFAToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.ID != "1" || claims.Subject != "1" {
+		t.Errorf("claims = %+v, want a challenge for user 1", claims)
+	}
+
+	if _, err := ValidateAccessToken(token); err != ErrInvalidTokenType {
+		t.Errorf("ValidateAccessToken(mfa token) error = %v, want %v", err, ErrInvalidTokenType)
+	}
+	if _, err := ValidateRefreshToken(token); err != ErrInvalidTokenType {
+		t.Errorf("ValidateRefreshToken(mfa token) error = %v, want %v", err, ErrInvalidTokenType)
+	}
+
+	access, _, err := (&TokenConfig{ID: "1", Username: "user"}).GenerateAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidateMFAToken(access); err != ErrInvalidTokenType {
+		t.Errorf("ValidateMFAToken(access token) error = %v, want %v", err, ErrInvalidTokenType)
+	}
+}
+
+func TestAuthenticationIsCarriedInBothTokens(t *testing.T) {
+	initTestKeys(t)
+
+	auth := NewAuthentication(AMRPassword)
+	if auth.ACR != ACRSingleFactor {
+		t.Errorf("acr of %v = %q, want %q", auth.AMR, auth.ACR, ACRSingleFactor)
+	}
+	auth = auth.Add(AMRTOTP)
+	if auth.ACR != ACRMultiFactor || !auth.UsedAny(AMRTOTP) {
+		t.Errorf("acr of %v = %q, want %q", auth.AMR, auth.ACR, ACRMultiFactor)
+	}
+
+	tc := TokenConfig{ID: "1", Username: "user", Authentication: auth}
+	access, _, err := tc.GenerateAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	refresh, _, err := tc.GenerateRefreshToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accessClaims, err := ValidateAccessToken(access)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshClaims, err := ValidateRefreshToken(refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, got := range []Authentication{accessClaims.Authentication, refreshClaims.Authentication} {
+		if got.ACR != auth.ACR || got.AuthTime != auth.AuthTime || len(got.AMR) != 2 {
+			t.Errorf("authentication = %+v, want %+v", got, auth)
+		}
+	}
+
+	if age := (Authentication{}).Age(); age < 24*time.Hour {
+		t.Errorf("age of an unknown authentication = %v, want it to be too old for any policy", age)
+	}
+}
+
+func TestDeviceTokenIsNamedByItsDevice(t *testing.T) {
+	initTestKeys(t)
+
+	exp := time.Now().UTC().Add(30 * 24 * time.Hour)
+	token, _, err := GenerateDeviceToken("1", "device-1", exp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := ValidateDeviceToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.ID != "1" || claims.TokenID != "device-1" || claims.ExpiresAt != exp.Unix() {
+		t.Errorf("claims = %+v, want device-1 of user 1", claims)
+	}
+
+	if _, err := ValidateAccessToken(token); err != ErrInvalidTokenType {
+		t.Errorf("ValidateAccessToken(device token) error = %v, want %v", err, ErrInvalidTokenType)
+	}
+	if _, err := ValidateMFAToken(token); err != ErrInvalidTokenType {
+		t.Errorf("ValidateMFAToken(device token) error = %v, want %v", err, ErrInvalidTokenType)
+	}
+}
+
+func TestPasswordChangeTokenGrantsNoAccess(t *testing.T) {
+	initTestKeys(t)
+
+	token, exp, err := GeneratePasswordChangeToken("1", "user", "PASSWORD_EXPIRED", NewAuthentication(AMRPassword))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(exp) > passwordChangeTokenExp {
+		t.Errorf("expires in %v, want at most %v", time.Until(exp), passwordChangeTokenExp)
+	}
+
+	claims, err := ValidatePasswordChangeToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.ID != "1" || claims.Reason != "PASSWORD_EXPIRED" {
+		t.Errorf("claims = %+v, want an expired password of user 1", claims)
+	}
+
+	if _, err := ValidateAccessToken(token); err != ErrInvalidTokenType {
+		t.Errorf("ValidateAccessToken(password change token) error = %v, want %v", err, ErrInvalidTokenType)
+	}
+	if _, err := ValidateMFAToken(token); err != ErrInvalidTokenType {
+		t.Errorf("ValidateMFAToken(password change token) error = %v, want %v", err, ErrInvalidTokenType)
+	}
+
+	// UpdatePassword revokes the token once it was used
+	revoked := revokedIDs{claims.TokenID: true}
+	SetRevocationList(revoked)
+	defer SetRevocationList(nil)
+	if _, err := ValidatePasswordChangeToken(token); err != ErrTokenRevoked {
+		t.Errorf("ValidatePasswordChangeToken(used token) error = %v, want %v", err, ErrTokenRevoked)
+	}
+}
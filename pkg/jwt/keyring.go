@@ -0,0 +1,289 @@
+package jwt
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yasinsaee/go-user-service/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrNoSigningKey = errors.New("no active signing key")
+
+type (
+	// SigningKey is one entry of the key ring.
+	// A key is published as soon as it is created, signs tokens from ActivatesAt
+	// until a newer key activates, and keeps verifying tokens until ExpiresAt.
+	SigningKey struct {
+		ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+		KID         string             `bson:"kid" json:"kid"`
+		Algorithm   string             `bson:"algorithm" json:"algorithm"`
+		PrivateKey  []byte             `bson:"private_key" json:"private_key"`
+		PublicKey   []byte             `bson:"public_key" json:"public_key"`
+		CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+		ActivatesAt time.Time          `bson:"activates_at" json:"activates_at"`
+		RetiredAt   time.Time          `bson:"retired_at,omitempty" json:"retired_at,omitempty"`
+		ExpiresAt   time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	}
+
+	// KeyStore persists the key ring so every replica signs and verifies with the same keys.
+	KeyStore interface {
+		List() ([]*SigningKey, error)
+		Save(key *SigningKey) error
+		Delete(kid string) error
+	}
+
+	keyRing struct {
+		mu   sync.RWMutex
+		keys []*SigningKey // ordered by ActivatesAt
+	}
+)
+
+var ring = &keyRing{}
+
+// isRetired reports whether the key has been superseded by a newer signing key.
+func (k *SigningKey) isRetired() bool {
+	return !k.RetiredAt.IsZero()
+}
+
+func (k *SigningKey) isExpired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && k.ExpiresAt.Before(now)
+}
+
+func (r *keyRing) set(keys []*SigningKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ActivatesAt.Before(keys[j].ActivatesAt)
+	})
+
+	r.mu.Lock()
+	r.keys = keys
+	r.mu.Unlock()
+}
+
+// signing returns the newest key that has already activated.
+func (r *keyRing) signing() (*SigningKey, error) {
+	now := time.Now().UTC()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i := len(r.keys) - 1; i >= 0; i-- {
+		k := r.keys[i]
+		if !k.ActivatesAt.After(now) && !k.isRetired() {
+			return k, nil
+		}
+	}
+	return nil, ErrNoSigningKey
+}
+
+// find returns the key with the given id as long as it may still verify tokens.
+func (r *keyRing) find(kid string) (*SigningKey, error) {
+	now := time.Now().UTC()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, k := range r.keys {
+		if k.KID == kid && !k.isExpired(now) {
+			return k, nil
+		}
+	}
+	return nil, ErrUnknownKeyID
+}
+
+// published returns every key that verifiers should know about, including pending ones.
+func (r *keyRing) published() []*SigningKey {
+	now := time.Now().UTC()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keys := make([]*SigningKey, 0, len(r.keys))
+	for _, k := range r.keys {
+		if !k.isExpired(now) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// newSigningKey builds a key ring entry from a PEM encoded key pair,
+// the algorithm is detected from the keys themselves.
+func newSigningKey(privateKey, publicKey []byte, activatesAt time.Time) (*SigningKey, error) {
+	_, alg, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	pub, publicAlg, err := parsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if alg != publicAlg {
+		return nil, errors.New("private and public key do not belong to the same algorithm")
+	}
+
+	kid, err := keyID(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{
+		KID:         kid,
+		Algorithm:   alg,
+		PrivateKey:  privateKey,
+		PublicKey:   publicKey,
+		CreatedAt:   time.Now().UTC(),
+		ActivatesAt: activatesAt,
+	}, nil
+}
+
+// generateSigningKey creates a fresh key pair for the configured algorithm.
+func generateSigningKey(activatesAt time.Time) (*SigningKey, error) {
+	privateKey, publicKey, err := generateKeyPair(conf.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	return newSigningKey(privateKey, publicKey, activatesAt)
+}
+
+// MaxTokenLifetime is the longest lifetime of any issued token, a retired key
+// keeps verifying that long and revocations are kept that long.
+func MaxTokenLifetime() time.Duration {
+	access := time.Hour * time.Duration(conf.AccessTokenExp)
+	refresh := time.Hour * 24 * time.Duration(conf.RefreshTokenExp)
+	return max(access, refresh, conf.ClientTokenExp, conf.DeviceTokenExp, mfaTokenExp, passwordChangeTokenExp)
+}
+
+// bootstrapKeys makes sure the store holds at least one signing key.
+// The configured PEM pair is imported on first start so tokens issued before
+// the key ring existed stay valid.
+func bootstrapKeys() error {
+	keys, err := conf.KeyStore.List()
+	if err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return nil
+	}
+
+	var key *SigningKey
+	if len(conf.PrivateKey) > 0 && len(conf.PublicKey) > 0 {
+		key, err = newSigningKey(conf.PrivateKey, conf.PublicKey, time.Now().UTC())
+	} else {
+		key, err = generateSigningKey(time.Now().UTC())
+	}
+	if err != nil {
+		return err
+	}
+	return conf.KeyStore.Save(key)
+}
+
+// ReloadKeys syncs the key ring with the store, retiring superseded keys and
+// dropping the ones that outlived every token they signed.
+func ReloadKeys() error {
+	if conf.KeyStore == nil {
+		return nil
+	}
+
+	keys, err := conf.KeyStore.List()
+	if err != nil {
+		return err
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ActivatesAt.Before(keys[j].ActivatesAt)
+	})
+
+	now := time.Now().UTC()
+	live := make([]*SigningKey, 0, len(keys))
+	for i, k := range keys {
+		// keys stored before the algorithm was recorded are detected from their PEM
+		if k.Algorithm == "" {
+			if _, k.Algorithm, err = parsePublicKey(k.PublicKey); err != nil {
+				return err
+			}
+		}
+
+		if k.isExpired(now) {
+			if err := conf.KeyStore.Delete(k.KID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// a key retires once the next one has taken over signing
+		if !k.isRetired() && i+1 < len(keys) && !keys[i+1].ActivatesAt.After(now) {
+			k.RetiredAt = keys[i+1].ActivatesAt
+			k.ExpiresAt = k.RetiredAt.Add(MaxTokenLifetime())
+			if err := conf.KeyStore.Save(k); err != nil {
+				return err
+			}
+		}
+		live = append(live, k)
+	}
+
+	ring.set(live)
+	return nil
+}
+
+// RotateKey generates a new key pair. It is published right away and takes
+// over signing after the configured grace period, or immediately when asked to.
+func RotateKey(immediate bool) (*SigningKey, error) {
+	if conf.KeyStore == nil {
+		return nil, errors.New("key rotation requires a key store")
+	}
+
+	activatesAt := time.Now().UTC().Add(conf.RotationGracePeriod)
+	if immediate {
+		activatesAt = time.Now().UTC()
+	}
+
+	key, err := generateSigningKey(activatesAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := conf.KeyStore.Save(key); err != nil {
+		return nil, err
+	}
+
+	return key, ReloadKeys()
+}
+
+// rotateIfDue starts a rotation when the signing key is older than the rotation interval
+// and no newer key is already waiting to take over.
+func rotateIfDue() error {
+	if conf.RotationInterval <= 0 {
+		return nil
+	}
+
+	current, err := ring.signing()
+	if err != nil {
+		return err
+	}
+	for _, k := range ring.published() {
+		if k.ActivatesAt.After(current.ActivatesAt) {
+			return nil
+		}
+	}
+
+	if time.Since(current.ActivatesAt) < conf.RotationInterval {
+		return nil
+	}
+	_, err = RotateKey(false)
+	return err
+}
+
+// MaintainKeys periodically reloads the key ring and rotates keys when due.
+// It is meant to run in its own goroutine for the lifetime of the process.
+func MaintainKeys(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := ReloadKeys(); err != nil {
+			logger.Error("jwt: reload keys failed: ", err.Error())
+			continue
+		}
+		if err := rotateIfDue(); err != nil {
+			logger.Error("jwt: key rotation failed: ", err.Error())
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package jwt
+
+import "time"
+
+// TokenTypeMFA is a login challenge, it proves the password and asks for the second factor.
+const TokenTypeMFA TokenType = "mfa"
+
+// mfaTokenExp is how long the user has to enter the second factor
+const mfaTokenExp = 5 * time.Minute
+
+// MFAClaims is the challenge returned by a login when the user has a second factor.
+// It grants nothing, VerifyMFA exchanges it together with a code for real tokens.
+type MFAClaims struct {
+	ID       string    `json:"id"`
+	Username string    `json:"username"`
+	Type     TokenType `json:"type"`
+	// the first factor, VerifyMFA adds the second one
+	Authentication
+	RegisteredClaims
+}
+
+// GenerateMFAToken issues a short lived challenge for the user who passed the first factor auth.
+func GenerateMFAToken(userID, username string, auth Authentication) (string, time.Time, error) {
+	exp := time.Now().UTC().Add(mfaTokenExp)
+	claims := &MFAClaims{
+		ID:               userID,
+		Username:         username,
+		Type:             TokenTypeMFA,
+		Authentication:   auth,
+		RegisteredClaims: newRegisteredClaims(userID, exp),
+	}
+
+	return signToken(claims)
+}
+
+// ValidateMFAToken verifies a challenge issued by GenerateMFAToken.
+func ValidateMFAToken(token string) (*MFAClaims, error) {
+	claims := &MFAClaims{}
+	if err := parseClaims(trimBearer(token), claims); err != nil {
+		return nil, err
+	}
+
+	if claims.Type != TokenTypeMFA {
+		return nil, ErrInvalidTokenType
+	}
+
+	if err := checkRevoked(claims.RegisteredClaims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
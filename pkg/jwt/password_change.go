@@ -0,0 +1,53 @@
+package jwt
+
+import "time"
+
+// TokenTypePasswordChange is the answer to a login whose password has to be changed first.
+const TokenTypePasswordChange TokenType = "password_change"
+
+// passwordChangeTokenExp is how long the user has to choose a new password
+const passwordChangeTokenExp = 10 * time.Minute
+
+// PasswordChangeClaims is returned by a login instead of tokens when the user has to
+// change the password. It grants nothing but UpdatePassword for its own user.
+type PasswordChangeClaims struct {
+	ID       string    `json:"id"`
+	Username string    `json:"username"`
+	Type     TokenType `json:"type"`
+	Reason   string    `json:"reason"` // why the password has to be changed
+	Authentication
+	RegisteredClaims
+}
+
+// GeneratePasswordChangeToken issues a short lived token that lets the user change the password.
+func GeneratePasswordChangeToken(userID, username, reason string, auth Authentication) (string, time.Time, error) {
+	exp := time.Now().UTC().Add(passwordChangeTokenExp)
+	claims := &PasswordChangeClaims{
+		ID:               userID,
+		Username:         username,
+		Type:             TokenTypePasswordChange,
+		Reason:           reason,
+		Authentication:   auth,
+		RegisteredClaims: newRegisteredClaims(userID, exp),
+	}
+
+	return signToken(claims)
+}
+
+// ValidatePasswordChangeToken verifies a token issued by GeneratePasswordChangeToken.
+func ValidatePasswordChangeToken(token string) (*PasswordChangeClaims, error) {
+	claims := &PasswordChangeClaims{}
+	if err := parseClaims(trimBearer(token), claims); err != nil {
+		return nil, err
+	}
+
+	if claims.Type != TokenTypePasswordChange {
+		return nil, ErrInvalidTokenType
+	}
+
+	if err := checkRevoked(claims.RegisteredClaims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
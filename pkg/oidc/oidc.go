@@ -0,0 +1,328 @@
+// Package oidc signs users in at upstream OpenID Connect providers, e.g. Google
+// Workspace or Keycloak, with the authorization code flow.
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	jwt2 "github.com/yasinsaee/go-user-service/pkg/jwt"
+)
+
+var (
+	ErrInvalidIDToken = errors.New("invalid id token")
+	ErrUnknownKeyID   = errors.New("id token signed with an unknown key")
+)
+
+const (
+	// tolerated clock skew between us and the provider
+	leeway = time.Minute
+	// keys are refetched at most this often when a token names an unknown kid
+	keyRefreshInterval = time.Minute
+)
+
+type (
+	// Config describes an upstream provider and our registration at it.
+	Config struct {
+		Name         string
+		Issuer       string // discovery is fetched from Issuer + /.well-known/openid-configuration
+		ClientID     string
+		ClientSecret string
+		Scopes       []string
+	}
+
+	// Metadata is the part of the provider's discovery document we use.
+	Metadata struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+
+	// Claims are the ID token claims used to find or provision the local user.
+	Claims struct {
+		Issuer            string        `json:"iss"`
+		Subject           string        `json:"sub"`
+		Audience          jwt2.Audience `json:"aud"`
+		AuthorizedParty   string        `json:"azp,omitempty"`
+		ExpiresAt         int64         `json:"exp"`
+		IssuedAt          int64         `json:"iat"`
+		Nonce             string        `json:"nonce,omitempty"`
+		Email             string        `json:"email,omitempty"`
+		EmailVerified     flexibleBool  `json:"email_verified,omitempty"`
+		Name              string        `json:"name,omitempty"`
+		GivenName         string        `json:"given_name,omitempty"`
+		FamilyName        string        `json:"family_name,omitempty"`
+		PreferredUsername string        `json:"preferred_username,omitempty"`
+		Picture           string        `json:"picture,omitempty"`
+	}
+
+	// Provider talks to one upstream provider. Its discovery document and keys are
+	// fetched on first use, the keys again when a token names an unknown kid.
+	Provider struct {
+		conf   Config
+		client *http.Client
+
+		mu          sync.Mutex
+		metadata    *Metadata
+		keys        map[string]jsonWebKey
+		keysFetched time.Time
+	}
+
+	tokenResponse struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+)
+
+// flexibleBool accepts email_verified as a boolean or, like some providers send it, a string.
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	var v bool
+	if err := json.Unmarshal(data, &v); err == nil {
+		*b = flexibleBool(v)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*b = flexibleBool(s == "true")
+	return nil
+}
+
+// Valid implements jwt.Claims, the checks need the provider and are done by Verify.
+func (c *Claims) Valid() error {
+	return nil
+}
+
+// IsEmailVerified reports whether the provider vouches for the email address.
+func (c *Claims) IsEmailVerified() bool {
+	return c.Email != "" && bool(c.EmailVerified)
+}
+
+// NewProvider returns a provider, nothing is fetched until it is used.
+func NewProvider(conf Config) *Provider {
+	if len(conf.Scopes) == 0 {
+		conf.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &Provider{
+		conf:   conf,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the configured name of the provider.
+func (p *Provider) Name() string {
+	return p.conf.Name
+}
+
+// CodeChallenge returns the S256 PKCE challenge of verifier.
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns where to send the user to sign in at the provider.
+func (p *Provider) AuthCodeURL(ctx context.Context, redirectURI, state, nonce, verifier string) (string, error) {
+	m, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(m.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.conf.ClientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("scope", strings.Join(p.conf.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", CodeChallenge(verifier))
+	query.Set("code_challenge_method", "S256")
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Exchange redeems the code at the provider's token endpoint and returns the verified ID token claims.
+func (p *Provider) Exchange(ctx context.Context, code, redirectURI, verifier, nonce string) (*Claims, error) {
+	m, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// RFC 6749 section 2.3.1 wants the credentials form encoded before they go into basic auth
+	req.SetBasicAuth(url.QueryEscape(p.conf.ClientID), url.QueryEscape(p.conf.ClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("oidc: token response of %s: %w", p.conf.Name, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc: token request to %s failed: %s %s", p.conf.Name, token.Error, token.ErrorDescription)
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("oidc: %s returned no id token", p.conf.Name)
+	}
+
+	return p.Verify(ctx, token.IDToken, nonce)
+}
+
+// Verify checks the signature of an ID token against the provider's keys and its
+// claims against our registration (OpenID Connect Core section 3.1.3.7).
+func (p *Provider) Verify(ctx context.Context, raw, nonce string) (*Claims, error) {
+	m, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &Claims{}
+	parser := &jwt.Parser{ValidMethods: []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}}
+	if _, err := parser.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		return p.verificationKey(ctx, token)
+	}); err != nil {
+		if ve, ok := err.(*jwt.ValidationError); ok && ve.Inner != nil {
+			return nil, ve.Inner
+		}
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	switch {
+	case claims.Issuer != m.Issuer:
+		return nil, fmt.Errorf("%w: issuer %q", ErrInvalidIDToken, claims.Issuer)
+	case !claims.Audience.Contains(p.conf.ClientID):
+		return nil, fmt.Errorf("%w: not addressed to us", ErrInvalidIDToken)
+	case len(claims.Audience) > 1 && claims.AuthorizedParty != p.conf.ClientID:
+		return nil, fmt.Errorf("%w: issued to another party", ErrInvalidIDToken)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	case claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(leeway)):
+		return nil, fmt.Errorf("%w: expired", ErrInvalidIDToken)
+	case claims.IssuedAt == 0 || now.Add(leeway).Before(time.Unix(claims.IssuedAt, 0)):
+		return nil, fmt.Errorf("%w: issued in the future", ErrInvalidIDToken)
+	case claims.Nonce != nonce:
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	return claims, nil
+}
+
+// discover fetches the discovery document once, it must name the configured issuer.
+func (p *Provider) discover(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	m := &Metadata{}
+	if err := p.getJSON(ctx, strings.TrimSuffix(p.conf.Issuer, "/")+"/.well-known/openid-configuration", m); err != nil {
+		return nil, err
+	}
+	if m.Issuer != p.conf.Issuer {
+		return nil, fmt.Errorf("oidc: %s announces issuer %q, configured %q", p.conf.Name, m.Issuer, p.conf.Issuer)
+	}
+	if m.AuthorizationEndpoint == "" || m.TokenEndpoint == "" || m.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc: discovery document of %s is incomplete", p.conf.Name)
+	}
+
+	p.metadata = m
+	return m, nil
+}
+
+// verificationKey resolves the key of the token's kid, refetching the provider's
+// keys once when it rotated to a key we have not seen yet.
+func (p *Provider) verificationKey(ctx context.Context, token *jwt.Token) (crypto.PublicKey, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key, ok := p.keys[kid]
+	if !ok && time.Since(p.keysFetched) > keyRefreshInterval {
+		if err := p.fetchKeys(ctx); err != nil {
+			return nil, err
+		}
+		key, ok = p.keys[kid]
+	}
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+
+	if key.Alg != "" && key.Alg != token.Method.Alg() {
+		return nil, fmt.Errorf("%w: algorithm does not match its key", ErrInvalidIDToken)
+	}
+	return key.publicKey()
+}
+
+// fetchKeys replaces the cached keys, the caller holds p.mu.
+func (p *Provider) fetchKeys(ctx context.Context) error {
+	set := &jsonWebKeySet{}
+	if err := p.getJSON(ctx, p.metadata.JWKSURI, set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]jsonWebKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		keys[k.Kid] = k
+	}
+	p.keys = keys
+	p.keysFetched = time.Now()
+	return nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,195 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// mockProvider is a minimal OpenID Connect provider issuing ID tokens for one code.
+type mockProvider struct {
+	*httptest.Server
+	key      *rsa.PrivateKey
+	kid      string
+	clientID string
+	code     string
+	verifier string
+	claims   jwt.MapClaims
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockProvider{key: key, kid: "key-1", clientID: "our-client", code: "good-code"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Metadata{
+			Issuer:                m.URL,
+			AuthorizationEndpoint: m.URL + "/authorize",
+			TokenEndpoint:         m.URL + "/token",
+			JWKSURI:               m.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jsonWebKeySet{Keys: []jsonWebKey{{
+			Kty: "RSA",
+			Kid: m.kid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if id != m.clientID || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(tokenResponse{Error: "invalid_client"})
+			return
+		}
+		if r.FormValue("code") != m.code || CodeChallenge(r.FormValue("code_verifier")) != CodeChallenge(m.verifier) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(tokenResponse{Error: "invalid_grant"})
+			return
+		}
+		json.NewEncoder(w).Encode(tokenResponse{IDToken: m.sign(t, m.claims)})
+	})
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+	return m
+}
+
+func (m *mockProvider) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = m.kid
+	signed, err := token.SignedString(m.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func (m *mockProvider) idClaims(nonce string) jwt.MapClaims {
+	now := time.Now().Unix()
+	return jwt.MapClaims{
+		"iss":            m.URL,
+		"sub":            "upstream-42",
+		"aud":            m.clientID,
+		"exp":            now + 300,
+		"iat":            now,
+		"nonce":          nonce,
+		"email":          "jane@example.com",
+		"email_verified": true,
+		"name":           "Jane Doe",
+	}
+}
+
+func (m *mockProvider) provider() *Provider {
+	return NewProvider(Config{Name: "mock", Issuer: m.URL, ClientID: m.clientID, ClientSecret: "secret"})
+}
+
+func TestExchangeVerifiesIDToken(t *testing.T) {
+	m := newMockProvider(t)
+	m.verifier = "verifier-of-the-login"
+	m.claims = m.idClaims("nonce-1")
+	p := m.provider()
+	ctx := context.Background()
+
+	authURL, err := p.AuthCodeURL(ctx, "https://us/callback", "state-1", "nonce-1", m.verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(authURL)
+	if q := u.Query(); q.Get("code_challenge") != CodeChallenge(m.verifier) || q.Get("state") != "state-1" || q.Get("client_id") != m.clientID {
+		t.Errorf("AuthCodeURL() = %s, missing the request parameters", authURL)
+	}
+
+	claims, err := p.Exchange(ctx, m.code, "https://us/callback", m.verifier, "nonce-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != "upstream-42" || !claims.IsEmailVerified() || claims.Name != "Jane Doe" {
+		t.Errorf("claims = %+v, want the upstream user", claims)
+	}
+
+	if _, err := p.Exchange(ctx, m.code, "https://us/callback", "another-verifier", "nonce-1"); err == nil {
+		t.Error("Exchange() accepted a wrong code verifier")
+	}
+}
+
+func TestVerifyRejectsForeignTokens(t *testing.T) {
+	m := newMockProvider(t)
+	p := m.provider()
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		modify func(jwt.MapClaims)
+	}{
+		{"other audience", func(c jwt.MapClaims) { c["aud"] = "someone-else" }},
+		{"other issuer", func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{"expired", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{"replayed nonce", func(c jwt.MapClaims) { c["nonce"] = "old-nonce" }},
+	}
+	for _, tt := range tests {
+		claims := m.idClaims("nonce-1")
+		tt.modify(claims)
+		if _, err := p.Verify(ctx, m.sign(t, claims), "nonce-1"); err == nil {
+			t.Errorf("%s: Verify() accepted the token", tt.name)
+		}
+	}
+
+	// a token signed by a key the provider does not publish
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, m.idClaims("nonce-1"))
+	token.Header["kid"] = m.kid
+	forged, err := token.SignedString(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Verify(ctx, forged, "nonce-1"); err == nil {
+		t.Error("Verify() accepted a token with a forged signature")
+	}
+}
+
+func TestVerifyPicksUpRotatedKeys(t *testing.T) {
+	m := newMockProvider(t)
+	p := m.provider()
+	ctx := context.Background()
+
+	if _, err := p.Verify(ctx, m.sign(t, m.idClaims("n")), "n"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the provider rotates, its new kid is unknown to the cached key set
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.key, m.kid = key, "key-2"
+	p.keysFetched = time.Time{}
+
+	if _, err := p.Verify(ctx, m.sign(t, m.idClaims("n")), "n"); err != nil {
+		t.Errorf("Verify() after rotation error = %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package password
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2idParams are the cost parameters of new Argon2id hashes.
+type Argon2idParams struct {
+	Memory      uint32 // in KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32 // in bytes
+	KeyLength   uint32 // in bytes
+}
+
+// DefaultArgon2idParams are the minimum recommended by OWASP, 19 MiB of memory and two passes.
+var DefaultArgon2idParams = Argon2idParams{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+const argon2idPrefix = "$argon2id$"
+
+var b64 = base64.RawStdEncoding
+
+type argon2id struct {
+	params  Argon2idParams
+	pepper  []byte
+	keyID   string            // names the pepper in the hashes, empty without one
+	peppers map[string][]byte // that still verify by keyID, nil for hashes without pepper
+}
+
+// NewArgon2id returns the Argon2id scheme. A pepper, a secret kept outside the database,
+// is mixed into every password with HMAC-SHA256 and named by the keyid parameter of the
+// hashes. Hashes without pepper and those of the previous peppers still verify and are
+// outdated, only hashes of a pepper that is lost no longer verify.
+func NewArgon2id(params Argon2idParams, pepper []byte, previous ...[]byte) Scheme {
+	s := &argon2id{params: params, peppers: map[string][]byte{"": nil}}
+	for _, p := range previous {
+		if len(p) > 0 {
+			s.peppers[pepperKeyID(p)] = p
+		}
+	}
+	if len(pepper) > 0 {
+		s.pepper = pepper
+		s.keyID = pepperKeyID(pepper)
+		s.peppers[s.keyID] = pepper
+	}
+	return s
+}
+
+func pepperKeyID(pepper []byte) string {
+	sum := sha256.Sum256(pepper)
+	return b64.EncodeToString(sum[:6])
+}
+
+func (s *argon2id) Identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2idPrefix)
+}
+
+// Hash returns $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>[,keyid=<pepper>]$<salt>$<key>.
+func (s *argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, s.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	p := s.params
+	key := argon2.IDKey(peppered(s.pepper, password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+
+	params := fmt.Sprintf("m=%d,t=%d,p=%d", p.Memory, p.Iterations, p.Parallelism)
+	if s.keyID != "" {
+		params += ",keyid=" + s.keyID
+	}
+	return fmt.Sprintf("%sv=%d$%s$%s$%s", argon2idPrefix, argon2.Version, params, b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+}
+
+func (s *argon2id) Verify(password, encoded string) bool {
+	h, err := parseArgon2id(encoded)
+	if err != nil {
+		return false
+	}
+	pepper, ok := s.peppers[h.keyID]
+	if !ok {
+		return false
+	}
+	key := argon2.IDKey(peppered(pepper, password), h.salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, uint32(len(h.key)))
+	return subtle.ConstantTimeCompare(key, h.key) == 1
+}
+
+func (s *argon2id) Outdated(encoded string) bool {
+	h, err := parseArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return h.version != argon2.Version ||
+		h.params.Memory < s.params.Memory ||
+		h.params.Iterations < s.params.Iterations ||
+		h.params.Parallelism < s.params.Parallelism ||
+		uint32(len(h.key)) < s.params.KeyLength ||
+		h.keyID != s.keyID
+}
+
+// peppered is what gets hashed, the password itself or its HMAC under the pepper.
+func peppered(pepper []byte, password string) []byte {
+	if pepper == nil {
+		return []byte(password)
+	}
+	mac := hmac.New(sha256.New, pepper)
+	mac.Write([]byte(password))
+	return mac.Sum(nil)
+}
+
+type argon2idHash struct {
+	version int
+	params  Argon2idParams
+	keyID   string
+	salt    []byte
+	key     []byte
+}
+
+func parseArgon2id(encoded string) (*argon2idHash, error) {
+	// "", "argon2id", "v=19", params, salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, fmt.Errorf("not an argon2id hash")
+	}
+
+	h := &argon2idHash{}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &h.version); err != nil {
+		return nil, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	for _, param := range strings.Split(parts[3], ",") {
+		name, value, _ := strings.Cut(param, "=")
+		var err error
+		switch name {
+		case "m":
+			_, err = fmt.Sscan(value, &h.params.Memory)
+		case "t":
+			_, err = fmt.Sscan(value, &h.params.Iterations)
+		case "p":
+			_, err = fmt.Sscan(value, &h.params.Parallelism)
+		case "keyid":
+			h.keyID = value
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid argon2id parameter %s: %w", name, err)
+		}
+	}
+	if h.params.Memory == 0 || h.params.Iterations == 0 || h.params.Parallelism == 0 {
+		return nil, fmt.Errorf("argon2id parameters are missing")
+	}
+
+	var err error
+	if h.salt, err = b64.DecodeString(parts[4]); err != nil {
+		return nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	if h.key, err = b64.DecodeString(parts[5]); err != nil || len(h.key) == 0 {
+		return nil, fmt.Errorf("invalid argon2id key")
+	}
+	return h, nil
+}
@@ -0,0 +1,40 @@
+package password
+
+import (
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type bcryptScheme struct {
+	cost int
+}
+
+// NewBcrypt returns the bcrypt scheme, it hashes at cost. bcrypt reads only the first
+// 72 bytes of a password, Hash refuses longer ones.
+func NewBcrypt(cost int) Scheme {
+	return &bcryptScheme{cost: cost}
+}
+
+// Identifies accepts the $2a$, $2b$ and $2y$ variants, which differ only in bugs of
+// old implementations.
+func (s *bcryptScheme) Identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func (s *bcryptScheme) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (s *bcryptScheme) Verify(password, encoded string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)) == nil
+}
+
+func (s *bcryptScheme) Outdated(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost < s.cost
+}
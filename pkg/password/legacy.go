@@ -0,0 +1,177 @@
+package password
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Formats of password hashes imported from other applications. They are only verified,
+// a user's legacy hash is replaced by a native one at the first login.
+const (
+	FormatMD5Salted    = "md5_salted"    // hex MD5 of the salt followed by the password
+	FormatSHA256Salted = "sha256_salted" // hex SHA-256 of the salt followed by the password, the salt may be empty
+	FormatPBKDF2SHA256 = "pbkdf2_sha256" // pbkdf2_sha256$<iterations>$<salt>$<base64 key>, as Django stores it
+	FormatScrypt       = "scrypt"        // scrypt$<salt>$<N>$<r>$<p>$<base64 key>, as Django stores it
+	FormatBcrypt       = "bcrypt"        // $2y$..., as Laravel stores it, or Django's bcrypt$$2b$...
+	FormatBcryptSHA256 = "bcrypt_sha256" // Django's bcrypt_sha256$$2b$..., bcrypt of the hex SHA-256 of the password
+)
+
+// limits of the cost parameters, a crafted import must not tie up the server at every login
+const (
+	maxPBKDF2Iterations = 10_000_000
+	maxScryptMemory     = 256 << 20 // bytes, 128*N*r
+	maxBcryptCost       = 16        // a few seconds, each step doubles it up to 31
+)
+
+// ErrUnknownFormat is returned for a legacy hash format that is not supported.
+var ErrUnknownFormat = errors.New("unknown password hash format")
+
+// CheckLegacy reports whether hash is well formed in format, so a broken import is
+// refused instead of locking the user out later.
+func CheckLegacy(format, hash string) error {
+	switch format {
+	case FormatMD5Salted:
+		return checkHex(hash, md5.Size)
+	case FormatSHA256Salted:
+		return checkHex(hash, sha256.Size)
+	case FormatPBKDF2SHA256:
+		_, err := parsePBKDF2(hash)
+		return err
+	case FormatScrypt:
+		_, err := parseScrypt(hash)
+		return err
+	case FormatBcrypt, FormatBcryptSHA256:
+		_, err := parseBcrypt(format, hash)
+		return err
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// VerifyLegacy checks password against a hash in one of the legacy formats.
+func VerifyLegacy(format, password, hash, salt string) bool {
+	switch format {
+	case FormatMD5Salted:
+		sum := md5.Sum([]byte(salt + password))
+		return equalHex(sum[:], hash)
+	case FormatSHA256Salted:
+		sum := sha256.Sum256([]byte(salt + password))
+		return equalHex(sum[:], hash)
+	case FormatPBKDF2SHA256:
+		h, err := parsePBKDF2(hash)
+		if err != nil {
+			return false
+		}
+		key := pbkdf2.Key([]byte(password), []byte(h.salt), h.iterations, len(h.key), sha256.New)
+		return subtle.ConstantTimeCompare(key, h.key) == 1
+	case FormatScrypt:
+		h, err := parseScrypt(hash)
+		if err != nil {
+			return false
+		}
+		key, err := scrypt.Key([]byte(password), []byte(h.salt), h.n, h.r, h.p, len(h.key))
+		return err == nil && subtle.ConstantTimeCompare(key, h.key) == 1
+	case FormatBcrypt:
+		h, err := parseBcrypt(format, hash)
+		return err == nil && bcrypt.CompareHashAndPassword(h, []byte(password)) == nil
+	case FormatBcryptSHA256:
+		h, err := parseBcrypt(format, hash)
+		if err != nil {
+			return false
+		}
+		sum := sha256.Sum256([]byte(password))
+		digest := hex.EncodeToString(sum[:])
+		return bcrypt.CompareHashAndPassword(h, []byte(digest)) == nil
+	default:
+		return false
+	}
+}
+
+func checkHex(hash string, size int) error {
+	b, err := hex.DecodeString(hash)
+	if err != nil || len(b) != size {
+		return fmt.Errorf("expected %d hex digits", size*2)
+	}
+	return nil
+}
+
+func equalHex(sum []byte, hash string) bool {
+	b, err := hex.DecodeString(hash)
+	return err == nil && subtle.ConstantTimeCompare(sum, b) == 1
+}
+
+// parseBcrypt strips the algorithm Django puts in front of the bcrypt hash and checks its cost.
+func parseBcrypt(format, hash string) ([]byte, error) {
+	h := []byte(strings.TrimPrefix(hash, format+"$"))
+	cost, err := bcrypt.Cost(h)
+	if err != nil {
+		return nil, err
+	}
+	if cost > maxBcryptCost {
+		return nil, fmt.Errorf("bcrypt cost %d is too expensive", cost)
+	}
+	return h, nil
+}
+
+type pbkdf2Hash struct {
+	iterations int
+	salt       string
+	key        []byte
+}
+
+func parsePBKDF2(hash string) (*pbkdf2Hash, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != FormatPBKDF2SHA256 {
+		return nil, fmt.Errorf("expected pbkdf2_sha256$<iterations>$<salt>$<key>")
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations < 1 || iterations > maxPBKDF2Iterations {
+		return nil, fmt.Errorf("invalid pbkdf2 iterations %q", parts[1])
+	}
+	key, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("invalid pbkdf2 key")
+	}
+	return &pbkdf2Hash{iterations: iterations, salt: parts[2], key: key}, nil
+}
+
+type scryptHash struct {
+	salt    string
+	n, r, p int
+	key     []byte
+}
+
+func parseScrypt(hash string) (*scryptHash, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != FormatScrypt {
+		return nil, fmt.Errorf("expected scrypt$<salt>$<N>$<r>$<p>$<key>")
+	}
+	h := &scryptHash{salt: parts[1]}
+	for i, v := range []*int{&h.n, &h.r, &h.p} {
+		n, err := strconv.Atoi(parts[i+2])
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid scrypt parameter %q", parts[i+2])
+		}
+		*v = n
+	}
+	if h.n > maxScryptMemory/128/h.r || h.p > 16 {
+		return nil, fmt.Errorf("scrypt parameters are too expensive")
+	}
+	key, err := base64.StdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("invalid scrypt key")
+	}
+	h.key = key
+	return h, nil
+}
@@ -0,0 +1,49 @@
+// Package password hashes user passwords. New hashes use Argon2id (RFC 9106) in the PHC
+// string format, hashes of other schemes are still verified so they can be replaced at
+// the user's next login.
+package password
+
+// Scheme is one format of password hashes.
+type Scheme interface {
+	// Identifies reports whether encoded is a hash of this scheme.
+	Identifies(encoded string) bool
+	Hash(password string) (string, error)
+	Verify(password, encoded string) bool
+	// Outdated reports whether encoded was made with weaker parameters, or another
+	// pepper, than the scheme hashes with now.
+	Outdated(encoded string) bool
+}
+
+// Hasher hashes with its current scheme and verifies hashes of every scheme it knows.
+type Hasher struct {
+	current Scheme
+	schemes []Scheme
+}
+
+// NewHasher returns a Hasher making hashes of current, which also verifies hashes of legacy.
+func NewHasher(current Scheme, legacy ...Scheme) *Hasher {
+	return &Hasher{
+		current: current,
+		schemes: append([]Scheme{current}, legacy...),
+	}
+}
+
+// Hash returns a new hash of password in the current scheme.
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+// Verify checks password against encoded. rehash is true when encoded should be replaced
+// by a new hash of the password, because it is of a legacy scheme or outdated.
+func (h *Hasher) Verify(password, encoded string) (ok, rehash bool) {
+	for _, s := range h.schemes {
+		if !s.Identifies(encoded) {
+			continue
+		}
+		if !s.Verify(password, encoded) {
+			return false, false
+		}
+		return true, s != h.current || s.Outdated(encoded)
+	}
+	return false, false
+}
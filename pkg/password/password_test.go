@@ -0,0 +1,182 @@
+package password
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// cheap parameters, the tests are about the format and not the cost
+var testParams = Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestArgon2idHash(t *testing.T) {
+	s := NewArgon2id(testParams, nil)
+
+	hash, err := s.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Fatalf("unexpected hash %s", hash)
+	}
+	if !s.Verify("correct horse", hash) || s.Verify("correct horse!", hash) {
+		t.Fatal("expected only the password to verify")
+	}
+	if s.Outdated(hash) {
+		t.Fatal("expected a fresh hash to be current")
+	}
+
+	stronger := testParams
+	stronger.Iterations = 2
+	if !NewArgon2id(stronger, nil).Outdated(hash) {
+		t.Fatal("expected a hash with fewer iterations to be outdated")
+	}
+}
+
+// test vector of the reference implementation (github.com/P-H-C/phc-winner-argon2, src/test.c)
+func TestArgon2idVerifiesReferenceHash(t *testing.T) {
+	const hash = "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+	if !NewArgon2id(testParams, nil).Verify("password", hash) {
+		t.Fatal("expected the reference hash to verify")
+	}
+}
+
+func TestArgon2idPepper(t *testing.T) {
+	peppered := NewArgon2id(testParams, []byte("pepper"))
+
+	hash, err := peppered.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(hash, ",keyid=") || !peppered.Verify("correct horse", hash) {
+		t.Fatalf("expected a peppered hash to verify, got %s", hash)
+	}
+	if NewArgon2id(testParams, []byte("another")).Verify("correct horse", hash) {
+		t.Fatal("expected another pepper to fail")
+	}
+
+	plain, _ := NewArgon2id(testParams, nil).Hash("correct horse")
+	if !peppered.Verify("correct horse", plain) || peppered.Verify("correct horse!", plain) {
+		t.Fatal("expected a hash without pepper to still verify once a pepper is set")
+	}
+	if !peppered.Outdated(plain) {
+		t.Fatal("expected a hash without pepper to be outdated")
+	}
+}
+
+func TestHasherRehashesWithNewPepper(t *testing.T) {
+	plain, err := NewArgon2id(testParams, nil).Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a pepper set over existing hashes
+	h := NewHasher(NewArgon2id(testParams, []byte("pepper")))
+	if ok, rehash := h.Verify("correct horse", plain); !ok || !rehash {
+		t.Fatalf("expected a hash without pepper to verify and be rehashed, got %v, %v", ok, rehash)
+	}
+	old, err := h.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the pepper rotated, the old one kept as previous
+	rotated := NewHasher(NewArgon2id(testParams, []byte("rotated"), []byte("pepper")))
+	if ok, rehash := rotated.Verify("correct horse", old); !ok || !rehash {
+		t.Fatalf("expected a hash of the previous pepper to verify and be rehashed, got %v, %v", ok, rehash)
+	}
+	if ok, _ := rotated.Verify("correct horse!", old); ok {
+		t.Fatal("expected a wrong password to fail")
+	}
+	current, err := rotated.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, rehash := rotated.Verify("correct horse", current); !ok || rehash {
+		t.Fatalf("expected a hash of the current pepper to verify as is, got %v, %v", ok, rehash)
+	}
+}
+
+func TestHasherRehashesLegacyHashes(t *testing.T) {
+	h := NewHasher(NewArgon2id(testParams, nil), NewBcrypt(bcrypt.MinCost))
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, rehash := h.Verify("123456", string(legacy)); !ok || !rehash {
+		t.Fatalf("expected a bcrypt hash to verify and be rehashed, got %v, %v", ok, rehash)
+	}
+	if ok, _ := h.Verify("654321", string(legacy)); ok {
+		t.Fatal("expected a wrong password to fail")
+	}
+
+	current, err := h.Hash("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, rehash := h.Verify("123456", current); !ok || rehash {
+		t.Fatalf("expected a current hash to verify as is, got %v, %v", ok, rehash)
+	}
+
+	if ok, _ := h.Verify("123456", "123456"); ok {
+		t.Fatal("expected an unknown format not to verify")
+	}
+}
+
+// hashes of "correct horse", made with Python's hashlib like Django does
+func TestVerifyLegacy(t *testing.T) {
+	djangoBcrypt := func(password string) string {
+		sum := sha256.Sum256([]byte(password))
+		h, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(sum[:])), bcrypt.MinCost)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "bcrypt_sha256$" + string(h)
+	}
+	laravel, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		format, hash, salt string
+	}{
+		{FormatMD5Salted, "23899c6974cd8c90f085a14355699a5a", "s4lt"},
+		{FormatSHA256Salted, "c82d2c45132f6276034ddb3a8e74f65e5a89d6460eda3247d3ebafc6a07bb33a", "s4lt"},
+		{FormatPBKDF2SHA256, "pbkdf2_sha256$1000$seasalt$mQnueSakb748zqBAC1tmWVZsZbi2zPGZarEzTGdfmso=", ""},
+		{FormatScrypt, "scrypt$seasalt$1024$8$1$b9vqOe0B5HZh2f10Q9RPN9OFNPtmb+ezp5YOlnZsJQ0vQ5I3eq8Zt7GFMuqQ58xCxTIvVL1QCL/PTXEWr+RNHA==", ""},
+		{FormatBcrypt, strings.Replace(string(laravel), "$2a$", "$2y$", 1), ""},
+		{FormatBcryptSHA256, djangoBcrypt("correct horse"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if err := CheckLegacy(tt.format, tt.hash); err != nil {
+				t.Fatalf("expected a well formed hash, got %v", err)
+			}
+			if !VerifyLegacy(tt.format, "correct horse", tt.hash, tt.salt) {
+				t.Fatal("expected the password to verify")
+			}
+			if VerifyLegacy(tt.format, "correct horse!", tt.hash, tt.salt) {
+				t.Fatal("expected another password to fail")
+			}
+		})
+	}
+
+	if err := CheckLegacy("md4", "31d6cfe0d16ae931b73c59d7e0c089c0"); err != ErrUnknownFormat {
+		t.Fatalf("expected ErrUnknownFormat, got %v", err)
+	}
+	if err := CheckLegacy(FormatScrypt, "scrypt$salt$1073741824$8$1$AAAA"); err == nil {
+		t.Fatal("expected a too expensive scrypt hash to be refused")
+	}
+	expensive := strings.Replace(string(laravel), "$04$", "$31$", 1)
+	if err := CheckLegacy(FormatBcrypt, expensive); err == nil {
+		t.Fatal("expected a too expensive bcrypt hash to be refused")
+	}
+	if VerifyLegacy(FormatBcrypt, "correct horse", expensive, "") {
+		t.Fatal("expected a too expensive bcrypt hash not to be verified")
+	}
+}
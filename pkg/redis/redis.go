@@ -124,3 +124,49 @@ func Expire(key string, ttl time.Duration) error {
 	ctx := context.Background()
 	return DB.Client.Expire(ctx, key, ttl).Err()
 }
+
+// SAdd adds members to a set
+func SAdd(key string, members ...interface{}) error {
+	ctx := context.Background()
+	return DB.Client.SAdd(ctx, key, members...).Err()
+}
+
+// SMembers returns all members of a set
+func SMembers(key string) ([]string, error) {
+	ctx := context.Background()
+	return DB.Client.SMembers(ctx, key).Result()
+}
+
+// SRem removes members from a set
+func SRem(key string, members ...interface{}) error {
+	ctx := context.Background()
+	return DB.Client.SRem(ctx, key, members...).Err()
+}
+
+// Script is a Lua script that Redis runs atomically
+type Script = redis.Script
+
+// NewScript wraps a Lua script, it is loaded into Redis on first use
+func NewScript(src string) *Script {
+	return redis.NewScript(src)
+}
+
+// RunScript runs a script with its keys and arguments
+func RunScript(script *Script, keys []string, args ...interface{}) (interface{}, error) {
+	ctx := context.Background()
+	val, err := script.Run(ctx, DB.Client, keys, args...).Result()
+	if err == redis.Nil {
+		return nil, ErrKeyNotFound
+	}
+	return val, err
+}
+
+// GetDel returns the value of a key and deletes it atomically
+func GetDel(key string) (string, error) {
+	ctx := context.Background()
+	val, err := DB.Client.GetDel(ctx, key).Result()
+	if err == redis.Nil {
+		return "", ErrKeyNotFound
+	}
+	return val, err
+}
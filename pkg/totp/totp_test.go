@@ -0,0 +1,60 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 4226 appendix D
+func TestHOTP(t *testing.T) {
+	key := []byte("12345678901234567890")
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range want {
+		if got := hotp(key, uint64(counter), 6); got != code {
+			t.Errorf("hotp(%d) = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+// RFC 6238 appendix B, SHA-1 with 8 digits
+func TestTOTPVectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+	tests := map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	}
+	for unix, code := range tests {
+		if got := hotp(key, uint64(Step(time.Unix(unix, 0))), 8); got != code {
+			t.Errorf("totp(%d) = %s, want %s", unix, got, code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+
+	code, err := Code(secret, Step(now.Add(-Period)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	step, ok := Validate(secret, code, now)
+	if !ok || step != Step(now)-1 {
+		t.Fatalf("Validate() = %d, %v, want the previous step", step, ok)
+	}
+
+	if _, ok := Validate(secret, code, now.Add(2*Period)); ok {
+		t.Error("Validate() accepted a code outside the skew")
+	}
+	if _, ok := Validate(strings.ToLower(secret), code, now); !ok {
+		t.Error("Validate() refused a lower case secret")
+	}
+}
@@ -13,6 +13,9 @@ func ToObjectID(id interface{}) (primitive.ObjectID, error) {
 			return primitive.NilObjectID, err
 		}
 	}
+	if val, ok := id.(primitive.ObjectID); ok {
+		objID = val
+	}
 
 	return objID, nil
 }
@@ -13,11 +13,14 @@ const (
 	specialChars     = "!@#$%^&*"
 )
 
+// Deprecated: HashPassword hashes at bcrypt cost 8 and drops errors. Hash with a
+// password.Hasher instead.
 func HashPassword(password string) string {
 	bytes, _ := bcrypt.GenerateFromPassword([]byte(password), 8)
 	return string(bytes)
 }
 
+// Deprecated: use the Verify of a password.Hasher, which also verifies Argon2id hashes.
 func CheckPasswordHash(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
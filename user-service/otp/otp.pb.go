@@ -28,6 +28,7 @@ const (
 	OTPType_FORGOT_PASSWORD    OTPType = 0
 	OTPType_REGISTER           OTPType = 1
 	OTPType_EMAIL_VERIFICATION OTPType = 2
+	OTPType_LOGIN              OTPType = 3
 )
 
 // Enum value maps for OTPType.
@@ -36,11 +37,13 @@ var (
 		0: "FORGOT_PASSWORD",
 		1: "REGISTER",
 		2: "EMAIL_VERIFICATION",
+		3: "LOGIN",
 	}
 	OTPType_value = map[string]int32{
 		"FORGOT_PASSWORD":    0,
 		"REGISTER":           1,
 		"EMAIL_VERIFICATION": 2,
+		"LOGIN":              3,
 	}
 )
 
@@ -909,11 +912,12 @@ const file_user_service_otp_otp_proto_rawDesc = "" +
 	"\x04code\x18\x02 \x01(\tR\x04code\x12 \n" +
 	"\x04type\x18\x03 \x01(\x0e2\f.otp.OTPTypeR\x04type\"+\n" +
 	"\x13ValidateOTPResponse\x12\x14\n" +
-	"\x05valid\x18\x01 \x01(\bR\x05valid*D\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid*O\n" +
 	"\aOTPType\x12\x13\n" +
 	"\x0fFORGOT_PASSWORD\x10\x00\x12\f\n" +
 	"\bREGISTER\x10\x01\x12\x16\n" +
-	"\x12EMAIL_VERIFICATION\x10\x022\xad\x03\n" +
+	"\x12EMAIL_VERIFICATION\x10\x02\x12\t\n" +
+	"\x05LOGIN\x10\x032\xad\x03\n" +
 	"\n" +
 	"OTPService\x12:\n" +
 	"\tCreateOTP\x12\x15.otp.CreateOTPRequest\x1a\x16.otp.CreateOTPResponse\x12:\n" +